@@ -13,15 +13,24 @@ import (
 	"github.com/caarlos0/env/v9"
 	"github.com/pkg/errors"
 	health "github.com/tel-io/tel/v2/monitoring/heallth"
+	"github.com/tel-io/tel/v2/otlplog/logskd"
+	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+	"github.com/tel-io/tel/v2/pkg/metricview"
 	"github.com/tel-io/tel/v2/pkg/samplers"
+	sdkmetric "github.com/tel-io/tel/v2/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/zap/zapcore"
-	"google.golang.org/grpc/credentials"
 )
 
 var (
-	ErrNoTLS    = errors.New("no tls configuration")
-	ErrCaAppend = errors.New("append certs from pem")
+	ErrNoTLS                  = errors.New("no tls configuration")
+	ErrCaAppend               = errors.New("append certs from pem")
+	ErrUnknownProtocol        = errors.New("unknown exporter protocol")
+	ErrUnsupportedProtocol    = errors.New("exporter protocol is not supported by signal")
+	ErrUnknownTracesProcessor = errors.New("unknown traces processor")
+	ErrUnknownDetector        = errors.New("unknown resource detector")
+	ErrInvalidSampler         = errors.New("invalid traces sampler")
 )
 
 const (
@@ -34,11 +43,34 @@ const (
 
 const DisableLog = "none"
 
+// Exporter protocols supported by OTEL_EXPORTER_PROTOCOL
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+	ProtocolHTTPJSON     = "http/json"
+)
+
+// Resource detectors supported by OTEL_RESOURCE_DETECTORS
+const (
+	DetectorK8s         = "k8s"
+	DetectorContainer   = "container"
+	DetectorProcess     = "process"
+	DetectorEnvironment = "environment"
+	DetectorNone        = "none"
+)
+
+// Span processors supported by TRACES_PROCESSOR
+const (
+	TracesProcessorBatch   = "batch"
+	TracesProcessorDelayed = "delayed"
+)
+
 const (
 	neverSampler              = "never"
 	alwaysSampler             = "always"
 	traceIDRatioSampler       = "traceidratio"
 	statusTraceIDRatioSampler = "statustraceidratio"
+	rateLimitedSampler        = "ratelimited"
 )
 
 // Option interface used for setting optional config properties.
@@ -53,17 +85,59 @@ func (o optionFunc) apply(c *Config) {
 }
 
 type tracesConfig struct {
+	// Enable switches OTLP traces export, requires OTEL_ENABLE
+	Enable bool `env:"OTEL_TRACES_ENABLE" envDefault:"true"`
+
+	Export ExportConfig `envPrefix:"OTEL_EXPORTER_OTLP_TRACES_"`
+
 	EnableRetry               bool   `env:"TRACES_ENABLE_RETRY" envDefault:"false"`
 	Sampler                   string `env:"TRACES_SAMPLER" envDefault:"statustraceidratio:0.1"`
 	EnableSpanTrackLogMessage bool   `env:"TRACES_ENABLE_SPAN_TRACK_LOG_MESSAGE" envDefault:"false"`
 	EnableSpanTrackLogFields  bool   `env:"TRACES_ENABLE_SPAN_TRACK_LOG_FIELDS" envDefault:"true"`
-	CardinalityDetector       struct {
+
+	// SamplerRules picks sampler of root spans by name, kind, scope or attributes, TRACES_SAMPLER is fallback
+	SamplerRules string `env:"TRACES_SAMPLER_RULES"`
+
+	// ForceSample extracts forced sampling of the whole trace from Header or baggage tel.force_sample=1,
+	// logs of forced requests are written from LogLevel. Any client can force sampling,
+	// so enable it only on services behind trusted edge which strips the header from public requests
+	ForceSample struct {
+		Enable   bool          `env:"TRACES_FORCE_SAMPLE_ENABLE" envDefault:"false"`
+		Header   string        `env:"TRACES_FORCE_SAMPLE_HEADER" envDefault:"X-Tel-Force-Sample"`
+		LogLevel zapcore.Level `env:"TRACES_FORCE_SAMPLE_LOG_LEVEL" envDefault:"debug"`
+	}
+
+	// Processor of finished spans: batch or delayed
+	// delayed keeps traces with errors, slow traces and traces in trace-ID fraction (tail-based sampling)
+	Processor string `env:"TRACES_PROCESSOR" envDefault:"batch"`
+	Delayed   struct {
+		MaxLatency     time.Duration      `env:"TRACES_DELAYED_MAX_LATENCY" envDefault:"5s"`
+		OnError        bool               `env:"TRACES_DELAYED_ON_ERROR" envDefault:"true"`
+		Fraction       float64            `env:"TRACES_DELAYED_FRACTION" envDefault:"0.1"`
+		ScopeFractions map[string]float64 `env:"TRACES_DELAYED_SCOPE_FRACTIONS"`
+	}
+
+	CardinalityDetector struct {
 		Enable             bool          `env:"TRACES_CARDINALITY_DETECTOR_ENABLE" envDefault:"true"`
 		MaxCardinality     int           `env:"TRACES_CARDINALITY_DETECTOR_MAX_CARDINALITY" envDefault:"0"`
 		MaxInstruments     int           `env:"TRACES_CARDINALITY_DETECTOR_MAX_INSTRUMENTS" envDefault:"500"`
 		DiagnosticInterval time.Duration `env:"TRACES_CARDINALITY_DETECTOR_DIAGNOSTIC_INTERVAL" envDefault:"10m"`
+		// Mode drop or overflow, see cardinalitydetector.ModeDrop and cardinalitydetector.ModeOverflow
+		Mode string `env:"TRACES_CARDINALITY_DETECTOR_MODE" envDefault:"drop"`
+		// Estimator exact or hll, see cardinalitydetector.EstimatorExact and cardinalitydetector.EstimatorHyperLogLog
+		Estimator string        `env:"TRACES_CARDINALITY_DETECTOR_ESTIMATOR" envDefault:"exact"`
+		Window    time.Duration `env:"TRACES_CARDINALITY_DETECTOR_WINDOW" envDefault:"0"`
+		// Rules override detector by span name, see cardinalitydetector.ParseRules, limits apply to span start attributes
+		Rules     string `env:"TRACES_CARDINALITY_DETECTOR_RULES"`
+		RulesFile string `env:"TRACES_CARDINALITY_DETECTOR_RULES_FILE"`
+		// Gauges exports tel.cardinality.* gauges of span names and their attributes
+		Gauges bool `env:"TRACES_CARDINALITY_METRICS_ENABLE" envDefault:"true"`
+
+		rules []cardinalitydetector.Rule
 	}
 	sampler sdktrace.Sampler
+	// samplerErr is error of parsing Sampler, it's reported on start
+	samplerErr error
 }
 
 // TODO: Review overlapping options (WthInsecure, WithCompression, etc).
@@ -71,8 +145,15 @@ type tracesConfig struct {
 type OtelConfig struct {
 	Enable bool `env:"OTEL_ENABLE" envDefault:"true"`
 
+	// Protocol used by logs, traces and metrics exporters: grpc, http/protobuf or http/json
+	// http/json is supported by logs only, traces and metrics exporters are rejected with ErrUnsupportedProtocol
+	Protocol string `env:"OTEL_EXPORTER_PROTOCOL" envDefault:"grpc"`
+
 	// OtelAddr address where grpc open-telemetry exporter serve
 	Addr string `env:"OTEL_COLLECTOR_GRPC_ADDR" envDefault:"127.0.0.1:4317"`
+
+	// HTTPAddr address where http open-telemetry exporter serve
+	HTTPAddr string `env:"OTEL_COLLECTOR_HTTP_ADDR" envDefault:"127.0.0.1:4318"`
 	// WithInsecure controls whether a client verifies the server's
 	// certificate chain and host name. If InsecureSkipVerify is true, crypto/tls
 	// accepts any certificate presented by the server and any host name in that
@@ -93,7 +174,18 @@ type OtelConfig struct {
 	// Disable WithInsecure option if set
 	ServerName string `env:"OTEL_COLLECTOR_TLS_SERVER_NAME"`
 
+	// Propagators of trace context and baggage: tracecontext, baggage, b3, b3multi, jaeger or none
+	Propagators []string `env:"OTEL_PROPAGATORS" envDefault:"tracecontext,baggage"`
+
+	// Headers are sent with export requests of each signal: key1=value1,key2=value2
+	Headers Headers `env:"OTEL_EXPORTER_OTLP_HEADERS"`
+
 	Logs struct {
+		// Enable switches OTLP logs export, requires OTEL_ENABLE
+		Enable bool `env:"OTEL_LOGS_ENABLE" envDefault:"true"`
+
+		Export ExportConfig `envPrefix:"OTEL_EXPORTER_OTLP_LOGS_"`
+
 		// OtelClient is logger of otel clients
 		OtelClient bool `env:"LOGGING_OTEL_CLIENT"`
 
@@ -105,17 +197,56 @@ type OtelConfig struct {
 		MaxMessageSize            int           `env:"LOGS_MAX_MESSAGE_SIZE" envDefault:"256"`
 		MaxMessagesPerSecond      int           `env:"LOGS_MAX_MESSAGES_PER_SECOND" envDefault:"100"`
 		MaxLevelMessagesPerSecond string        `env:"LOGS_MAX_LEVEL_MESSAGES_PER_SECOND" envDefault:""`
+		MaxRequestSize            int           `env:"LOGS_MAX_REQUEST_SIZE" envDefault:"4194304"`
 	}
 
 	Traces tracesConfig
 
+	// Buffer keeps export requests on disk while collector is unreachable and replays them on reconnect.
+	// Works with grpc protocol only.
+	Buffer struct {
+		Enable bool `env:"OTEL_BUFFER_ENABLE" envDefault:"false"`
+		// Dir is root of signals buffers locked by single process.
+		// Default <tmp>/tel/<namespace>_<service> has numbered slots for instances running on the same host
+		Dir     string        `env:"OTEL_BUFFER_DIR"`
+		MaxSize int64         `env:"OTEL_BUFFER_MAX_SIZE" envDefault:"104857600"`
+		MaxAge  time.Duration `env:"OTEL_BUFFER_MAX_AGE" envDefault:"1h"`
+	}
+
 	Metrics struct {
+		// Enable switches OTLP metrics export, requires OTEL_ENABLE
+		Enable bool `env:"OTEL_METRICS_ENABLE" envDefault:"true"`
+
+		Export ExportConfig `envPrefix:"OTEL_EXPORTER_OTLP_METRICS_"`
+
+		// ExemplarFilter links data points to traces: always_on, trace_based or always_off
+		ExemplarFilter string `env:"OTEL_METRICS_EXEMPLAR_FILTER" envDefault:"trace_based"`
+
+		// Prometheus serves metrics for scraping on /metrics of monitor, works without OTEL_METRICS_ENABLE
+		Prometheus bool `env:"METRICS_PROMETHEUS_ENABLE" envDefault:"false"`
+
+		// Views change aggregation, attributes and names of instruments, see metricview.Parse
+		Views     string `env:"METRICS_VIEWS"`
+		ViewsFile string `env:"METRICS_VIEWS_FILE"`
+
 		EnableRetry         bool `env:"METRICS_ENABLE_RETRY" envDefault:"false"`
 		CardinalityDetector struct {
 			Enable             bool          `env:"METRICS_CARDINALITY_DETECTOR_ENABLE" envDefault:"true"`
 			MaxCardinality     int           `env:"METRICS_CARDINALITY_DETECTOR_MAX_CARDINALITY" envDefault:"100"`
 			MaxInstruments     int           `env:"METRICS_CARDINALITY_DETECTOR_MAX_INSTRUMENTS" envDefault:"500"`
 			DiagnosticInterval time.Duration `env:"METRICS_CARDINALITY_DETECTOR_DIAGNOSTIC_INTERVAL" envDefault:"10m"`
+			// Mode drop or overflow, see cardinalitydetector.ModeDrop and cardinalitydetector.ModeOverflow
+			Mode string `env:"METRICS_CARDINALITY_DETECTOR_MODE" envDefault:"drop"`
+			// Estimator exact or hll, see cardinalitydetector.EstimatorExact and cardinalitydetector.EstimatorHyperLogLog
+			Estimator string        `env:"METRICS_CARDINALITY_DETECTOR_ESTIMATOR" envDefault:"exact"`
+			Window    time.Duration `env:"METRICS_CARDINALITY_DETECTOR_WINDOW" envDefault:"0"`
+			// Rules override detector by instrument name, see cardinalitydetector.ParseRules
+			Rules     string `env:"METRICS_CARDINALITY_DETECTOR_RULES"`
+			RulesFile string `env:"METRICS_CARDINALITY_DETECTOR_RULES_FILE"`
+			// Gauges exports tel.cardinality.* gauges of instruments and their attributes
+			Gauges bool `env:"METRICS_CARDINALITY_METRICS_ENABLE" envDefault:"true"`
+
+			rules []cardinalitydetector.Rule
 		}
 	}
 
@@ -129,11 +260,20 @@ type OtelConfig struct {
 	}
 
 	bucketView []HistogramOpt
+
+	// components injected by options, they work along with OTLP exporters or replace them if signal is disabled
+	spanExporters  []sdktrace.SpanExporter
+	spanProcessors []sdktrace.SpanProcessor
+	logExporters   []logskd.Exporter
+	logProcessors  []logskd.LogProcessor
+	metricReaders  []metric.Reader
 }
 
 type MonitorConfig struct {
 	Enable      bool   `env:"MONITOR_ENABLE" envDefault:"true"`
 	MonitorAddr string `env:"MONITOR_ADDR" envDefault:"0.0.0.0:8011"`
+	// LogLevelWrite allows PUT /log/level, it's allowed with Debug as well
+	LogLevelWrite bool `env:"MONITOR_LOG_LEVEL_WRITE_ENABLE" envDefault:"false"`
 
 	healthChecker []health.Checker
 }
@@ -148,6 +288,10 @@ type Config struct {
 	LogEncode string `env:"LOG_ENCODE" envDefault:"json"`
 	Debug     bool   `env:"DEBUG" envDefault:"false"`
 
+	// ResourceDetectors fill resource attributes: k8s, container, process, environment or none
+	// k8s pod name becomes service instance id
+	ResourceDetectors []string `env:"OTEL_RESOURCE_DETECTORS" envDefault:"k8s,container,process,environment"`
+
 	MonitorConfig
 	OtelConfig
 }
@@ -163,29 +307,61 @@ func DefaultConfig() Config {
 	host = strings.ToLower(strings.ReplaceAll(host, "-", "_"))
 
 	// Please keep in sync with envDefault in struct
-	return Config{
+	c := Config{
 		Service:     host,
 		Version:     "dev",
 		Namespace:   "default",
 		Environment: "dev",
 		LogEncode:   "json",
 		LogLevel:    "info",
+		ResourceDetectors: []string{
+			DetectorK8s, DetectorContainer, DetectorProcess, DetectorEnvironment,
+		},
 		MonitorConfig: MonitorConfig{
 			Enable:      true,
 			MonitorAddr: "0.0.0.0:8011",
 		},
 		OtelConfig: OtelConfig{
+			Protocol:                   ProtocolGRPC,
 			Addr:                       "127.0.0.1:4317",
+			HTTPAddr:                   "127.0.0.1:4318",
 			WithInsecure:               true,
 			Enable:                     true,
 			WithCompression:            true,
 			MetricsPeriodicIntervalSec: 15,
-			Traces: tracesConfig{
-				Sampler: statusTraceIDRatioSampler + ":0.1",
-				sampler: sdktrace.NeverSample(),
-			},
+			Propagators:                []string{PropagatorTraceContext, PropagatorBaggage},
+			Traces:                     defaultTracesConfig(),
 		},
 	}
+
+	c.Logs.Enable = true
+	c.Metrics.Enable = true
+	c.Metrics.ExemplarFilter = sdkmetric.ExemplarFilterTraceBased
+	c.Metrics.CardinalityDetector.Gauges = true
+	c.Buffer.MaxSize = 100 << 20
+	c.Buffer.MaxAge = time.Hour
+
+	return c
+}
+
+func defaultTracesConfig() tracesConfig {
+	c := tracesConfig{
+		Enable:    true,
+		Sampler:   statusTraceIDRatioSampler + ":0.1",
+		Processor: TracesProcessorBatch,
+		sampler:   sdktrace.NeverSample(),
+	}
+
+	c.CardinalityDetector.Gauges = true
+
+	c.Delayed.MaxLatency = 5 * time.Second
+	c.Delayed.OnError = true
+	c.Delayed.Fraction = 0.1
+	c.ForceSample.Enable = false
+	c.ForceSample.Header = "X-Tel-Force-Sample"
+	c.ForceSample.LogLevel = zapcore.DebugLevel
+
+	return c
 }
 
 func DefaultDebugConfig() Config {
@@ -207,6 +383,7 @@ func GetConfigFromEnv() Config {
 			reflect.TypeOf([]byte{}): func(v string) (interface{}, error) {
 				return []byte(v), nil
 			},
+			reflect.TypeOf(Headers{}): parseHeaders,
 		},
 	})
 
@@ -236,11 +413,138 @@ func GetConfigFromEnv() Config {
 	} else if strings.HasPrefix(c.Traces.Sampler, statusTraceIDRatioSampler) {
 		fraction := parseSamplerFraction(c.Traces.Sampler)
 		c.Traces.sampler = samplers.StatusTraceIDRatioBased(fraction)
+	} else if strings.HasPrefix(c.Traces.Sampler, rateLimitedSampler) {
+		sampler, err := parseRateLimitedSampler(c.Traces.Sampler)
+		if err != nil {
+			c.Traces.samplerErr = err
+		} else {
+			c.Traces.sampler = sampler
+		}
 	}
 
 	return c
 }
 
+func (c *Config) validateDetectors() error {
+	for _, d := range c.ResourceDetectors {
+		switch d {
+		case DetectorK8s, DetectorContainer, DetectorProcess, DetectorEnvironment, DetectorNone:
+		default:
+			return errors.WithMessagef(ErrUnknownDetector, "%q", d)
+		}
+	}
+
+	return nil
+}
+
+func (c *tracesConfig) validateProcessor() error {
+	switch c.Processor {
+	case "", TracesProcessorBatch, TracesProcessorDelayed:
+		return nil
+	}
+
+	return errors.WithMessagef(ErrUnknownTracesProcessor, "%q", c.Processor)
+}
+
+// ruleSampler wraps sampler with rules, all decisions are parent based
+func (c *tracesConfig) ruleSampler() (sdktrace.Sampler, error) {
+	if c.samplerErr != nil {
+		return nil, c.samplerErr
+	}
+
+	if strings.TrimSpace(c.SamplerRules) == "" {
+		return c.sampler, nil
+	}
+
+	rules, err := samplers.ParseRules(c.SamplerRules)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.ParentBased(samplers.RuleBased(c.sampler, rules...)), nil
+}
+
+// cardinalityRules joins rules of env, file and code options, the first matching rule is used,
+// so env and file override code
+func cardinalityRules(raw, file string, rules []cardinalitydetector.Rule) ([]cardinalitydetector.Rule, error) {
+	raw, err := appendFile(raw, file)
+	if err != nil {
+		return nil, errors.WithMessage(err, "read rules file")
+	}
+
+	parsed, err := cardinalitydetector.ParseRules(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(parsed, rules...), nil
+}
+
+// metricViews joins views of env, file and WithHistogram, the first matching view is used
+func (c *OtelConfig) metricViews() ([]metricview.View, error) {
+	raw, err := appendFile(c.Metrics.Views, c.Metrics.ViewsFile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "read views file")
+	}
+
+	views, err := metricview.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(views, c.histogramViews()...), nil
+}
+
+func (c *OtelConfig) histogramViews() []metricview.View {
+	views := make([]metricview.View, 0, len(c.bucketView))
+	for _, opt := range c.bucketView {
+		views = append(views, metricview.View{Instrument: opt.MetricName, Buckets: opt.Bucket})
+	}
+
+	return views
+}
+
+// appendFile appends file content to raw settings on new line
+func appendFile(raw, file string) (string, error) {
+	if file == "" {
+		return raw, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	return raw + "\n" + string(b), nil
+}
+
+// parseRateLimitedSampler parses ratelimited:<traces per second>[:<min name share>], budget is spent on root spans only.
+// Rate should be positive, share is in (0,1]
+func parseRateLimitedSampler(s string) (sdktrace.Sampler, error) {
+	var opts []samplers.RateLimitedOption
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, errors.WithMessagef(ErrInvalidSampler, "%q", s)
+	}
+
+	perSecond, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || perSecond <= 0 {
+		return nil, errors.WithMessagef(ErrInvalidSampler, "%s rate %q", rateLimitedSampler, parts[1])
+	}
+
+	if len(parts) > 2 {
+		share, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || share <= 0 || share > 1 {
+			return nil, errors.WithMessagef(ErrInvalidSampler, "%s min name share %q", rateLimitedSampler, parts[2])
+		}
+
+		opts = append(opts, samplers.WithMinNameShare(share))
+	}
+
+	return sdktrace.ParentBased(samplers.RateLimited(perSecond, opts...)), nil
+}
+
 func parseSamplerFraction(s string) float64 {
 	var fraction float64 = 0
 
@@ -300,6 +604,61 @@ func WithHistogram(list ...HistogramOpt) Option {
 func WithTraceSampler(sampler sdktrace.Sampler) Option {
 	return optionFunc(func(config *Config) {
 		config.OtelConfig.Traces.sampler = sampler
+		config.OtelConfig.Traces.samplerErr = nil
+	})
+}
+
+// WithSpanExporter adds span exporter wrapped by batch processor.
+// Set Traces.Enable or OTEL_TRACES_ENABLE to false to replace OTLP exporter
+func WithSpanExporter(exporters ...sdktrace.SpanExporter) Option {
+	return optionFunc(func(config *Config) {
+		config.OtelConfig.spanExporters = append(config.OtelConfig.spanExporters, exporters...)
+	})
+}
+
+// WithSpanProcessor adds span processor, it runs before default processor
+func WithSpanProcessor(processors ...sdktrace.SpanProcessor) Option {
+	return optionFunc(func(config *Config) {
+		config.OtelConfig.spanProcessors = append(config.OtelConfig.spanProcessors, processors...)
+	})
+}
+
+// WithLogExporter adds log exporter wrapped by batch processor.
+// Set Logs.Enable or OTEL_LOGS_ENABLE to false to replace OTLP exporter
+func WithLogExporter(exporters ...logskd.Exporter) Option {
+	return optionFunc(func(config *Config) {
+		config.OtelConfig.logExporters = append(config.OtelConfig.logExporters, exporters...)
+	})
+}
+
+// WithLogProcessor adds log processor which gets every log along with default processor
+func WithLogProcessor(processors ...logskd.LogProcessor) Option {
+	return optionFunc(func(config *Config) {
+		config.OtelConfig.logProcessors = append(config.OtelConfig.logProcessors, processors...)
+	})
+}
+
+// WithMetricReader adds metric reader, instruments are still wrapped by cardinality detector.
+// Set Metrics.Enable or OTEL_METRICS_ENABLE to false to replace OTLP periodic reader
+func WithMetricReader(readers ...metric.Reader) Option {
+	return optionFunc(func(config *Config) {
+		config.OtelConfig.metricReaders = append(config.OtelConfig.metricReaders, readers...)
+	})
+}
+
+// WithMetricsCardinalityRules adds cardinality detector rules of metric instruments,
+// METRICS_CARDINALITY_DETECTOR_RULES take precedence
+func WithMetricsCardinalityRules(rules ...cardinalitydetector.Rule) Option {
+	return optionFunc(func(config *Config) {
+		config.Metrics.CardinalityDetector.rules = append(config.Metrics.CardinalityDetector.rules, rules...)
+	})
+}
+
+// WithTracesCardinalityRules adds cardinality detector rules of span names,
+// TRACES_CARDINALITY_DETECTOR_RULES take precedence
+func WithTracesCardinalityRules(rules ...cardinalitydetector.Rule) Option {
+	return optionFunc(func(config *Config) {
+		config.Traces.CardinalityDetector.rules = append(config.Traces.CardinalityDetector.rules, rules...)
 	})
 }
 
@@ -310,12 +669,41 @@ func (c *Config) Level() zapcore.Level {
 	return lvl
 }
 
+func (c *Config) validateLevel() error {
+	var lvl zapcore.Level
+
+	return errors.WithMessagef(lvl.Set(c.LogLevel), "zap set log level %q", c.LogLevel)
+}
+
 func (c *OtelConfig) IsTLS() bool {
 	return (len(c.Raw.Cert) > 0 && len(c.Raw.Key) > 0) || len(c.Raw.CA) > 0
 }
 
-// createClientTLSCredentials up to otel-collector
-func (c *OtelConfig) createClientTLSCredentials() (credentials.TransportCredentials, error) {
+// IsHTTP reports whether exporters should use OTLP/HTTP transport instead of gRPC
+func (c *OtelConfig) IsHTTP() bool {
+	return c.Protocol == ProtocolHTTPProtobuf || c.Protocol == ProtocolHTTPJSON
+}
+
+func (c *OtelConfig) validateProtocol() error {
+	switch c.Protocol {
+	case ProtocolGRPC, ProtocolHTTPProtobuf, ProtocolHTTPJSON:
+		return nil
+	}
+
+	return errors.WithMessagef(ErrUnknownProtocol, "%q", c.Protocol)
+}
+
+// validateProtobufProtocol checks Protocol of traces and metrics exporters, they have no json encoding
+func (c *OtelConfig) validateProtobufProtocol() error {
+	if c.Protocol == ProtocolHTTPJSON {
+		return errors.WithMessagef(ErrUnsupportedProtocol, "%q", c.Protocol)
+	}
+
+	return c.validateProtocol()
+}
+
+// createClientTLSConfig up to otel-collector
+func (c *OtelConfig) createClientTLSConfig() (*tls.Config, error) {
 	if !c.IsTLS() {
 		return nil, ErrNoTLS
 	}
@@ -341,5 +729,5 @@ func (c *OtelConfig) createClientTLSCredentials() (credentials.TransportCredenti
 		}
 	}
 
-	return credentials.NewTLS(cfg), nil
+	return cfg, nil
 }
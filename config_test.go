@@ -3,10 +3,17 @@ package tel
 import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+	"github.com/tel-io/tel/v2/pkg/metricview"
+	"github.com/tel-io/tel/v2/pkg/samplers"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -47,3 +54,193 @@ func Test_telemetry_TLS(t *testing.T) {
 	assert.NotEmpty(t, cfg.OtelConfig.Raw.Key)
 	assert.NotEmpty(t, cfg.OtelConfig.Raw.Cert)
 }
+
+func TestOtelConfig_Protocol(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.NoError(t, cfg.OtelConfig.validateProtocol())
+	assert.False(t, cfg.OtelConfig.IsHTTP())
+
+	for _, proto := range []string{ProtocolHTTPProtobuf, ProtocolHTTPJSON} {
+		cfg.OtelConfig.Protocol = proto
+		assert.NoError(t, cfg.OtelConfig.validateProtocol())
+		assert.True(t, cfg.OtelConfig.IsHTTP())
+	}
+
+	assert.NoError(t, cfg.OtelConfig.validateProtocol())
+	assert.ErrorIs(t, cfg.OtelConfig.validateProtobufProtocol(), ErrUnsupportedProtocol)
+
+	cfg.OtelConfig.Protocol = "udp"
+	assert.ErrorIs(t, cfg.OtelConfig.validateProtocol(), ErrUnknownProtocol)
+	assert.ErrorIs(t, cfg.OtelConfig.validateProtobufProtocol(), ErrUnknownProtocol)
+}
+
+func TestTracesConfig_Processor(t *testing.T) {
+	t.Setenv("TRACES_PROCESSOR", TracesProcessorDelayed)
+	t.Setenv("TRACES_DELAYED_MAX_LATENCY", "2s")
+	t.Setenv("TRACES_DELAYED_ON_ERROR", "false")
+	t.Setenv("TRACES_DELAYED_FRACTION", "0.5")
+	t.Setenv("TRACES_DELAYED_SCOPE_FRACTIONS", "db:0.01,http:1")
+
+	cfg := GetConfigFromEnv()
+	assert.NoError(t, cfg.Traces.validateProcessor())
+	assert.Equal(t, TracesProcessorDelayed, cfg.Traces.Processor)
+	assert.Equal(t, 2*time.Second, cfg.Traces.Delayed.MaxLatency)
+	assert.False(t, cfg.Traces.Delayed.OnError)
+	assert.Equal(t, 0.5, cfg.Traces.Delayed.Fraction)
+	assert.Equal(t, map[string]float64{"db": 0.01, "http": 1}, cfg.Traces.Delayed.ScopeFractions)
+
+	cfg.Traces.Processor = "simple"
+	assert.ErrorIs(t, cfg.Traces.validateProcessor(), ErrUnknownTracesProcessor)
+}
+
+func TestTracesConfig_SamplerRules(t *testing.T) {
+	t.Setenv("TRACES_SAMPLER", alwaysSampler)
+	t.Setenv("TRACES_SAMPLER_RULES", "http.route=/health=>drop; http.route=/metrics=>drop")
+
+	cfg := GetConfigFromEnv()
+
+	sampler, err := cfg.Traces.ruleSampler()
+	require.NoError(t, err)
+	assert.Equal(t, "ParentBased{root:RuleBased{AlwaysOffSampler,AlwaysOffSampler,AlwaysOnSampler},"+
+		"remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,"+
+		"localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}", sampler.Description())
+
+	cfg.Traces.SamplerRules = ""
+	sampler, err = cfg.Traces.ruleSampler()
+	require.NoError(t, err)
+	assert.Equal(t, sdktrace.AlwaysSample(), sampler)
+
+	cfg.Traces.SamplerRules = "http.route=/health"
+	_, err = cfg.Traces.ruleSampler()
+	assert.ErrorIs(t, err, samplers.ErrInvalidRule)
+}
+
+func TestTracesConfig_RateLimitedSampler(t *testing.T) {
+	t.Setenv("TRACES_SAMPLER", "ratelimited:100:0.05")
+
+	cfg := GetConfigFromEnv()
+	assert.True(t, strings.HasPrefix(cfg.Traces.sampler.Description(), "ParentBased{root:RateLimited{100,0.05},"))
+
+	cfg.Traces.SamplerRules = "http.route=/health=>drop"
+	sampler, err := cfg.Traces.ruleSampler()
+	require.NoError(t, err)
+	assert.Contains(t, sampler.Description(), "RuleBased{AlwaysOffSampler,ParentBased{root:RateLimited{100,0.05},")
+}
+
+func TestTracesConfig_RateLimitedSamplerInvalid(t *testing.T) {
+	for _, sampler := range []string{
+		"ratelimited",
+		"ratelimited:",
+		"ratelimited:0",
+		"ratelimited:-5",
+		"ratelimited:ten",
+		"ratelimited:100:0",
+		"ratelimited:100:1.5",
+		"ratelimited:100:0.1:1",
+	} {
+		t.Run(sampler, func(t *testing.T) {
+			t.Setenv("TRACES_SAMPLER", sampler)
+
+			cfg := GetConfigFromEnv()
+			_, err := cfg.Traces.ruleSampler()
+			assert.ErrorIs(t, err, ErrInvalidSampler)
+
+			// sampler of code replaces env one
+			WithTraceSampler(sdktrace.AlwaysSample()).apply(&cfg)
+			_, err = cfg.Traces.ruleSampler()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOtelConfig_SignalExport(t *testing.T) {
+	t.Setenv("OTEL_LOGS_ENABLE", "false")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "tenant=a,x-token=common")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "https://mimir.example.com:443/otlp/v1/metrics")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_HEADERS", "x-token=metrics")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_COMPRESSION", CompressionNone)
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "collector.tracing:4317")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_CERTIFICATE", certPath("ca.crt"))
+
+	cfg := GetConfigFromEnv()
+	assert.False(t, cfg.Logs.Enable)
+	assert.True(t, cfg.Traces.Enable)
+	assert.True(t, cfg.Metrics.Enable)
+
+	// tls of other tests could leak via env
+	cfg.Raw.CA, cfg.Raw.Cert, cfg.Raw.Key = nil, nil, nil
+
+	logs, err := cfg.OtelConfig.exportSettings(cfg.Logs.Export)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Addr, logs.endpoint)
+	assert.True(t, logs.compression)
+	assert.Nil(t, logs.tls)
+	assert.Equal(t, map[string]string{"tenant": "a", "x-token": "common"}, logs.headers)
+
+	metrics, err := cfg.OtelConfig.exportSettings(cfg.Metrics.Export)
+	require.NoError(t, err)
+	assert.Equal(t, "mimir.example.com:443", metrics.endpoint)
+	assert.Equal(t, "/otlp/v1/metrics", metrics.urlPath)
+	assert.False(t, metrics.insecure)
+	assert.False(t, metrics.compression)
+	assert.Equal(t, map[string]string{"tenant": "a", "x-token": "metrics"}, metrics.headers)
+
+	traces, err := cfg.OtelConfig.exportSettings(cfg.Traces.Export)
+	require.NoError(t, err)
+	assert.Equal(t, "collector.tracing:4317", traces.endpoint)
+	require.NotNil(t, traces.tls)
+	assert.NotNil(t, traces.tls.RootCAs)
+
+	cfg.Traces.Export.Compression = "zstd"
+	_, err = cfg.OtelConfig.exportSettings(cfg.Traces.Export)
+	assert.ErrorIs(t, err, ErrUnknownCompression)
+}
+
+func TestCardinalityRules(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rules")
+	require.NoError(t, os.WriteFile(file, []byte("# file rules\nhttp.*=>limit:10\n"), 0o600))
+
+	t.Setenv("METRICS_CARDINALITY_DETECTOR_RULES", "http.server.*=>limit:1000")
+	t.Setenv("METRICS_CARDINALITY_DETECTOR_RULES_FILE", file)
+
+	cfg := GetConfigFromEnv()
+	WithMetricsCardinalityRules(cardinalitydetector.Rule{Instrument: "*", Deny: []string{"user_id"}}).apply(&cfg)
+
+	rules, err := cardinalityRules(cfg.Metrics.CardinalityDetector.Rules,
+		cfg.Metrics.CardinalityDetector.RulesFile, cfg.Metrics.CardinalityDetector.rules)
+	require.NoError(t, err)
+	assert.Equal(t, []cardinalitydetector.Rule{
+		{Instrument: "http.server.*", MaxCardinality: 1000},
+		{Instrument: "http.*", MaxCardinality: 10},
+		{Instrument: "*", Deny: []string{"user_id"}},
+	}, rules)
+
+	_, err = cardinalityRules("http.*=>limit:0", "", nil)
+	assert.ErrorIs(t, err, cardinalitydetector.ErrInvalidRule)
+
+	_, err = cardinalityRules("", filepath.Join(t.TempDir(), "missing"), nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestOtelConfig_MetricViews(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "views")
+	require.NoError(t, os.WriteFile(file, []byte("# file views\nnoisy.*=>drop\n"), 0o600))
+
+	t.Setenv("METRICS_VIEWS", "http.*=>exponential")
+	t.Setenv("METRICS_VIEWS_FILE", file)
+
+	cfg := GetConfigFromEnv()
+	WithHistogram(HistogramOpt{MetricName: "db.duration", Bucket: []float64{1, 2}}).apply(&cfg)
+
+	views, err := cfg.OtelConfig.metricViews()
+	require.NoError(t, err)
+	assert.Equal(t, []metricview.View{
+		{Instrument: "http.*", Exponential: true},
+		{Instrument: "noisy.*", Drop: true},
+		{Instrument: "db.duration", Buckets: []float64{1, 2}},
+	}, views)
+
+	cfg.Metrics.Views = "http.*=>buckets:a"
+	_, err = cfg.OtelConfig.metricViews()
+	assert.ErrorIs(t, err, metricview.ErrInvalidView)
+}
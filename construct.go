@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/rand"
 
+	"github.com/tel-io/tel/v2/pkg/resourcedetector"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
@@ -25,24 +26,63 @@ const (
 var instanceGenerator = genInstanceID
 
 func CreateRes(ctx context.Context, l Config) *resource.Resource {
-	res, _ := resource.New(ctx,
+	opts := []resource.Option{
 		resource.WithFromEnv(),
-		// resource.WithProcess(),
 		// resource.WithTelemetrySDK(),
 		resource.WithHost(),
-		resource.WithAttributes(
-			// the service name used to display traces in backends + tempo UI by this field perform service selection
-			// key: service.name
-			ServiceNameKey.String(l.Service),
-			// key: service.version
-			semconv.ServiceVersionKey.String(l.Version),
-			ServiceInstanceIDKey.String(instanceGenerator(l.Service)),
-		),
-	)
+	}
+
+	opts = append(opts, detectorOptions(l)...)
+
+	opts = append(opts, resource.WithAttributes(
+		// the service name used to display traces in backends + tempo UI by this field perform service selection
+		// key: service.name
+		ServiceNameKey.String(l.Service),
+		// key: service.version
+		semconv.ServiceVersionKey.String(l.Version),
+	))
+
+	// detectors report partial errors, resource keeps detected attributes
+	res, _ := resource.New(ctx, opts...)
+
+	// pod name is stable instance id which correlates telemetry with pod
+	instance, ok := res.Set().Value(semconv.K8SPodNameKey)
+	if !ok {
+		instance = attribute.StringValue(instanceGenerator(l.Service))
+	}
+
+	res, _ = resource.Merge(res, resource.NewSchemaless(ServiceInstanceIDKey.String(instance.AsString())))
 
 	return res
 }
 
+func detectorOptions(l Config) []resource.Option {
+	var opts []resource.Option
+
+	for _, d := range l.ResourceDetectors {
+		switch d {
+		case DetectorK8s:
+			opts = append(opts, resource.WithDetectors(resourcedetector.NewK8s()))
+		case DetectorContainer:
+			opts = append(opts, resource.WithDetectors(resourcedetector.NewContainer()))
+		case DetectorProcess:
+			// command args are skipped as they could contain secrets
+			opts = append(opts,
+				resource.WithProcessPID(),
+				resource.WithProcessExecutableName(),
+				resource.WithProcessOwner(),
+				resource.WithProcessRuntimeName(),
+				resource.WithProcessRuntimeVersion(),
+				resource.WithProcessRuntimeDescription(),
+			)
+		case DetectorEnvironment:
+			opts = append(opts, resource.WithAttributes(semconv.DeploymentEnvironmentKey.String(l.Environment)))
+		}
+	}
+
+	return opts
+}
+
 func genInstanceID(srv string) string {
 	instSID := make([]byte, 4)
 	_, _ = rand.Read(instSID)
@@ -52,10 +92,10 @@ func genInstanceID(srv string) string {
 	return instance
 }
 
-func newLogger(l Config) *zap.Logger {
+func newLogger(l Config, lvl zap.AtomicLevel) (*zap.Logger, error) {
 	zapconfig := zap.NewProductionConfig()
 	zapconfig.EncoderConfig.EncodeTime = zapcore.RFC3339TimeEncoder
-	zapconfig.Level = zap.NewAtomicLevelAt(l.Level())
+	zapconfig.Level = lvl
 	zapconfig.Encoding = l.LogEncode
 
 	if zapconfig.Encoding == DisableLog {
@@ -66,13 +106,14 @@ func newLogger(l Config) *zap.Logger {
 	pl, err := zapconfig.Build(
 		zap.WithCaller(true),
 		zap.AddStacktrace(zapcore.ErrorLevel),
-		zap.IncreaseLevel(l.Level()),
 	)
-	handleErr(err, "zap build")
+	if err != nil {
+		return nil, err
+	}
 
 	zap.ReplaceGlobals(pl)
 
-	return pl
+	return pl, nil
 }
 
 // SetLogOutput debug function for duplicate input log into bytes.Buffer
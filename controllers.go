@@ -2,15 +2,27 @@ package tel
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/tel-io/tel/v2/monitoring"
+	"github.com/tel-io/tel/v2/otlplog"
 	"github.com/tel-io/tel/v2/otlplog/logskd"
 	"github.com/tel-io/tel/v2/otlplog/otlploggrpc"
+	"github.com/tel-io/tel/v2/otlplog/otlploghttp"
 	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
 	"github.com/tel-io/tel/v2/pkg/grpcerr"
+	"github.com/tel-io/tel/v2/pkg/loglevel"
+	"github.com/tel-io/tel/v2/pkg/metricview"
 	"github.com/tel-io/tel/v2/pkg/otelerr"
+	"github.com/tel-io/tel/v2/pkg/selfmetric"
+	"github.com/tel-io/tel/v2/pkg/wal"
 	"github.com/tel-io/tel/v2/pkg/zcore"
 	sdkmetric "github.com/tel-io/tel/v2/sdk/metric"
 	sdktrace "github.com/tel-io/tel/v2/sdk/trace"
@@ -18,14 +30,20 @@ import (
 	rt "go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	otelmetric "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/grpclog"
 )
 
@@ -36,55 +54,46 @@ import (
 // (https://github.com/open-telemetry/opentelemetry-specification/issues/982).
 var DefaultHistogramBoundaries = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
 
+// controllers start part of telemetry, returned shutdown func could be non-nil along with error
+// when controller is started in degraded mode
 type controllers interface {
-	apply(context.Context, *Telemetry) func(ctx context.Context)
+	apply(context.Context, *Telemetry) (func(ctx context.Context) error, error)
 }
 
 type oLog struct {
 	res *resource.Resource
+
+	// buf is on-disk buffer of exporter, closed on shutdown
+	buf *wal.WAL
 }
 
 func withOtelLog(res *resource.Resource) controllers {
 	return &oLog{res: res}
 }
 
-func (o *oLog) apply(ctx context.Context, t *Telemetry) func(context.Context) {
+func (o *oLog) apply(ctx context.Context, t *Telemetry) (func(context.Context) error, error) {
 	// exporter part
 	// this initiation controversy SRP, but right now we just speed up our development
-	opts := []otlploggrpc.Option{
-		otlploggrpc.WithEndpoint(t.cfg.OtelConfig.Addr),
-	}
-
-	if t.cfg.WithInsecure {
-		opts = append(opts, otlploggrpc.WithInsecure())
-	}
-
-	if t.cfg.OtelConfig.WithCompression {
-		opts = append(opts, otlploggrpc.WithCompressor("gzip"))
-	}
+	processors := t.cfg.logProcessors
 
-	if t.cfg.OtelConfig.IsTLS() {
-		cred, err := t.cfg.OtelConfig.createClientTLSCredentials()
-		handleErr(err, "Failed init TLS certificate")
-
-		if err == nil {
-			opts = append(opts, otlploggrpc.WithTLSCredentials(cred))
+	if t.cfg.Logs.Enable {
+		logExporter, err := o.exporter(ctx, t)
+		if err != nil {
+			return nil, errors.WithMessage(err, "create the collector log exporter")
 		}
-	}
 
-	if !t.cfg.Logs.EnableRetry {
-		logRetryOffOpt := otlploggrpc.WithRetry(otlploggrpc.RetryConfig{})
-		opts = append([]otlploggrpc.Option{logRetryOffOpt}, opts...)
+		processors = append(processors, logskd.NewBatchLogProcessorWithMeterProvider(logExporter, t.metricProvider))
 	}
 
-	logExporter, err := otlploggrpc.New(ctx, o.res, opts...)
-	handleErr(err, "Failed to create the collector log exporter")
+	for _, exp := range t.cfg.logExporters {
+		processors = append(processors, logskd.NewBatchLogProcessorWithMeterProvider(exp, t.metricProvider))
+	}
 
-	logProvider := logskd.NewBatchLogProcessor(logExporter)
+	logProvider := logskd.NewMultiLogProcessor(processors...)
 
 	cc := zcore.NewBodyCore(
 		logProvider,
-		zap.NewAtomicLevelAt(t.cfg.Level()),
+		t.levels.Add(loglevel.OutputOTLP, t.cfg.Level()),
 		zcore.WithMaxMessageSize(t.cfg.Logs.MaxMessageSize),
 		zcore.WithSyncInterval(t.cfg.Logs.SyncInterval),
 	)
@@ -118,54 +127,208 @@ func (o *oLog) apply(ctx context.Context, t *Telemetry) func(context.Context) {
 
 	zap.ReplaceGlobals(t.Logger)
 
-	return func(cxt context.Context) {
+	return func(cxt context.Context) error {
 		_ = logProvider.ForceFlush(ctx)
 
-		handleErr(logProvider.Shutdown(cxt), "log provider shutdown")
+		if err := logProvider.Shutdown(cxt); err != nil {
+			return multierr.Append(errors.WithMessage(err, "log provider shutdown"), closeBuffer(o.buf))
+		}
+
 		t.Info("OTEL log provider has been shutdown")
+
+		return closeBuffer(o.buf)
+	}, nil
+}
+
+func (o *oLog) exporter(ctx context.Context, t *Telemetry) (*otlplog.Exporter, error) {
+	if err := t.cfg.OtelConfig.validateProtocol(); err != nil {
+		return nil, err
 	}
+
+	if t.cfg.OtelConfig.IsHTTP() {
+		opts, err := o.httpOptions(t)
+		if err != nil {
+			return nil, err
+		}
+
+		return otlploghttp.New(ctx, o.res, opts...)
+	}
+
+	opts, err := o.grpcOptions(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return otlploggrpc.New(ctx, o.res, opts...)
+}
+
+func (o *oLog) grpcOptions(t *Telemetry) ([]otlploggrpc.Option, error) {
+	s, err := t.cfg.OtelConfig.exportSettings(t.cfg.Logs.Export)
+	if err != nil {
+		return nil, errors.WithMessage(err, "logs export settings")
+	}
+
+	opts := []otlploggrpc.Option{
+		otlploggrpc.WithEndpoint(s.endpoint),
+	}
+
+	if s.insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+
+	if s.compression {
+		opts = append(opts, otlploggrpc.WithCompressor("gzip"))
+	}
+
+	if len(s.headers) > 0 {
+		opts = append(opts, otlploggrpc.WithHeaders(s.headers))
+	}
+
+	if s.tls != nil {
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(s.tls)))
+	}
+
+	if t.cfg.Logs.MaxRequestSize > 0 {
+		opts = append(opts, otlploggrpc.WithMaxRequestSize(t.cfg.Logs.MaxRequestSize))
+	}
+
+	// logs client buffers requests by itself, it knows connection state
+	if o.buf = openBuffer(t, selfmetric.SignalLogs); o.buf != nil {
+		opts = append(opts, otlploggrpc.WithWAL(o.buf))
+	}
+
+	opts = append(opts,
+		otlploggrpc.WithMeterProvider(t.metricProvider),
+		otlploggrpc.WithDialOption(grpc.WithChainUnaryInterceptor(selfmetric.UnaryClientInterceptor(t.metricProvider))),
+	)
+
+	if !t.cfg.Logs.EnableRetry {
+		logRetryOffOpt := otlploggrpc.WithRetry(otlploggrpc.RetryConfig{})
+		opts = append([]otlploggrpc.Option{logRetryOffOpt}, opts...)
+	}
+
+	return opts, nil
+}
+
+func (o *oLog) httpOptions(t *Telemetry) ([]otlploghttp.Option, error) {
+	s, err := t.cfg.OtelConfig.exportSettings(t.cfg.Logs.Export)
+	if err != nil {
+		return nil, errors.WithMessage(err, "logs export settings")
+	}
+
+	opts := []otlploghttp.Option{
+		otlploghttp.WithEndpoint(s.endpoint),
+		otlploghttp.WithMeterProvider(t.metricProvider),
+	}
+
+	if s.urlPath != "" {
+		opts = append(opts, otlploghttp.WithURLPath(s.urlPath))
+	}
+
+	if s.insecure {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+
+	if s.compression {
+		opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+	}
+
+	if len(s.headers) > 0 {
+		opts = append(opts, otlploghttp.WithHeaders(s.headers))
+	}
+
+	if t.cfg.OtelConfig.Protocol == ProtocolHTTPJSON {
+		opts = append(opts, otlploghttp.WithMarshal(otlploghttp.MarshalJSON))
+	}
+
+	if s.tls != nil {
+		opts = append(opts, otlploghttp.WithTLSClientConfig(s.tls))
+	}
+
+	if t.cfg.Logs.MaxRequestSize > 0 {
+		opts = append(opts, otlploghttp.WithMaxRequestSize(t.cfg.Logs.MaxRequestSize))
+	}
+
+	if !t.cfg.Logs.EnableRetry {
+		logRetryOffOpt := otlploghttp.WithRetry(otlploghttp.RetryConfig{})
+		opts = append([]otlploghttp.Option{logRetryOffOpt}, opts...)
+	}
+
+	return opts, nil
 }
 
 // user otel.GetTracerProvider() to reach trace
 type oTrace struct {
 	res *resource.Resource
+
+	// buf is on-disk buffer of exporter, closed on shutdown
+	buf *wal.WAL
 }
 
 func withOtelTrace(res *resource.Resource) controllers {
 	return &oTrace{res: res}
 }
 
-func (o *oTrace) apply(ctx context.Context, t *Telemetry) func(context.Context) {
-	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(t.cfg.OtelConfig.Addr)}
-
-	if t.cfg.OtelConfig.WithInsecure {
-		opts = append(opts, otlptracegrpc.WithInsecure())
+func (o *oTrace) apply(ctx context.Context, t *Telemetry) (func(context.Context) error, error) {
+	if err := t.cfg.Traces.validateProcessor(); err != nil {
+		return nil, errors.WithMessage(err, "create the trace span processor")
 	}
 
-	if t.cfg.OtelConfig.WithCompression {
-		opts = append(opts, otlptracegrpc.WithCompressor("gzip"))
+	propagator, err := t.cfg.OtelConfig.propagator()
+	if err != nil {
+		return nil, errors.WithMessage(err, "create the propagator")
 	}
 
-	if t.cfg.OtelConfig.IsTLS() {
-		cred, err := t.cfg.OtelConfig.createClientTLSCredentials()
-		handleErr(err, "Failed init TLS certificate")
+	if mode := t.cfg.Traces.CardinalityDetector.Mode; mode != "" {
+		if err := cardinalitydetector.ValidateMode(mode); err != nil {
+			return nil, errors.WithMessage(err, "traces cardinality detector")
+		}
+	}
 
-		if err == nil {
-			opts = append(opts, otlptracegrpc.WithTLSCredentials(cred))
+	if estimator := t.cfg.Traces.CardinalityDetector.Estimator; estimator != "" {
+		if err := cardinalitydetector.ValidateEstimator(estimator, t.cfg.Traces.CardinalityDetector.Mode); err != nil {
+			return nil, errors.WithMessage(err, "traces cardinality detector")
 		}
 	}
 
-	if !t.cfg.Traces.EnableRetry {
-		traceRetryOffOpt := otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{})
-		opts = append([]otlptracegrpc.Option{traceRetryOffOpt}, opts...)
+	rules, err := cardinalityRules(t.cfg.Traces.CardinalityDetector.Rules,
+		t.cfg.Traces.CardinalityDetector.RulesFile, t.cfg.Traces.CardinalityDetector.rules)
+	if err != nil {
+		return nil, errors.WithMessage(err, "traces cardinality detector")
 	}
 
-	traceClient := otlptracegrpc.NewClient(opts...)
+	sampler, err := t.cfg.Traces.ruleSampler()
+	if err != nil {
+		return nil, errors.WithMessage(err, "create the trace sampler")
+	}
 
-	traceExp, err := otlptrace.New(ctx, traceClient)
-	handleErr(err, "Failed to create the collector trace exporter")
+	opts := []tracesdk.TracerProviderOption{
+		// forced decision of context goes first, see sdktrace.ForceSampling
+		tracesdk.WithSampler(sdktrace.NewSampler(sampler)),
+		tracesdk.WithResource(o.res),
+	}
 
-	bsp := tracesdk.NewBatchSpanProcessor(traceExp)
+	for _, p := range t.cfg.spanProcessors {
+		opts = append(opts, tracesdk.WithSpanProcessor(p))
+	}
+
+	if t.cfg.Traces.Enable {
+		client, err := o.client(t)
+		if err != nil {
+			return nil, errors.WithMessage(err, "create the collector trace exporter")
+		}
+
+		traceExp, err := otlptrace.New(ctx, client)
+		if err != nil {
+			return nil, errors.WithMessage(err, "create the collector trace exporter")
+		}
+
+		opts = append(opts, tracesdk.WithSpanProcessor(o.processor(t, traceExp)))
+	}
+
+	for _, exp := range t.cfg.spanExporters {
+		opts = append(opts, tracesdk.WithBatcher(exp))
+	}
 
 	tracerProvider := sdktrace.NewTracerProvider(ctx,
 		cardinalitydetector.NewOptions(
@@ -173,99 +336,200 @@ func (o *oTrace) apply(ctx context.Context, t *Telemetry) func(context.Context)
 			cardinalitydetector.WithMaxCardinality(t.cfg.Traces.CardinalityDetector.MaxCardinality),
 			cardinalitydetector.WithMaxInstruments(t.cfg.Traces.CardinalityDetector.MaxInstruments),
 			cardinalitydetector.WithCheckInterval(t.cfg.Traces.CardinalityDetector.DiagnosticInterval),
+			cardinalitydetector.WithMode(t.cfg.Traces.CardinalityDetector.Mode),
+			cardinalitydetector.WithEstimator(t.cfg.Traces.CardinalityDetector.Estimator),
+			cardinalitydetector.WithWindow(t.cfg.Traces.CardinalityDetector.Window),
+			cardinalitydetector.WithRules(rules...),
 		),
-		tracesdk.WithSampler(t.cfg.OtelConfig.Traces.sampler),
-		tracesdk.WithResource(o.res),
-		tracesdk.WithSpanProcessor(bsp),
+		opts...,
 	)
 
-	// set global propagator to tracecontext (the default is no-op).
-	otel.SetTextMapPropagator(
-		propagation.NewCompositeTextMapPropagator(
-			propagation.TraceContext{}, propagation.Baggage{},
-		))
+	// set global propagator, tracecontext and baggage by default (the otel default is no-op).
+	otel.SetTextMapPropagator(propagator)
 
 	otel.SetTracerProvider(tracerProvider)
 
 	t.traceProvider = tracerProvider
 	t.trace = tracerProvider.Tracer(GenServiceName(t.cfg.Namespace, t.cfg.Service) + "_tracer")
 
-	return func(cxt context.Context) {
-		handleErr(tracerProvider.Shutdown(cxt), "trace provider shutdown")
+	// metric controller goes first, cardinality gauges go to its sdk provider, they aren't limited by detector
+	if meterProvider, ok := t.metricProvider.(*sdkmetric.MeterProvider); ok && t.cfg.Traces.CardinalityDetector.Enable &&
+		t.cfg.Traces.CardinalityDetector.Gauges {
+		err := cardinalitydetector.RegisterMetrics(meterProvider.MeterProvider, selfmetric.SignalTraces, tracerProvider)
+		if err != nil {
+			t.Error("register traces cardinality metrics", zap.Error(err))
+		}
+	}
+
+	return func(cxt context.Context) error {
+		if err := tracerProvider.Shutdown(cxt); err != nil {
+			return multierr.Append(errors.WithMessage(err, "trace provider shutdown"), closeBuffer(o.buf))
+		}
+
 		t.Info("OTEL trace provider has been shutdown")
+
+		return closeBuffer(o.buf)
+	}, nil
+}
+
+func (o *oTrace) processor(t *Telemetry, exp tracesdk.SpanExporter) tracesdk.SpanProcessor {
+	if t.cfg.Traces.Processor != TracesProcessorDelayed {
+		return sdktrace.NewBatchSpanProcessorWithMeterProvider(exp, t.metricProvider)
 	}
+
+	return sdktrace.NewDelayedSpanProcessor(exp,
+		sdktrace.WithMaxLatency(t.cfg.Traces.Delayed.MaxLatency),
+		sdktrace.WithOnError(t.cfg.Traces.Delayed.OnError),
+		sdktrace.WithTraceIDFraction(t.cfg.Traces.Delayed.Fraction),
+		sdktrace.WithTraceIDFractionScoped(t.cfg.Traces.Delayed.ScopeFractions),
+		sdktrace.WithMeterProvider(t.metricProvider),
+	)
 }
 
-type oMetric struct {
-	res *resource.Resource
+func (o *oTrace) client(t *Telemetry) (otlptrace.Client, error) {
+	if err := t.cfg.OtelConfig.validateProtobufProtocol(); err != nil {
+		return nil, err
+	}
+
+	if t.cfg.OtelConfig.IsHTTP() {
+		return o.httpClient(t)
+	}
+
+	return o.grpcClient(t)
 }
 
-func withOtelMetric(res *resource.Resource) controllers {
-	return &oMetric{res: res}
+func (o *oTrace) grpcClient(t *Telemetry) (otlptrace.Client, error) {
+	s, err := t.cfg.OtelConfig.exportSettings(t.cfg.Traces.Export)
+	if err != nil {
+		return nil, errors.WithMessage(err, "traces export settings")
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(s.endpoint)}
+
+	if s.insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	if s.compression {
+		opts = append(opts, otlptracegrpc.WithCompressor("gzip"))
+	}
+
+	if len(s.headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(s.headers))
+	}
+
+	if s.tls != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(s.tls)))
+	}
+
+	o.buf = openBuffer(t, selfmetric.SignalTraces)
+	opts = append(opts, otlptracegrpc.WithDialOption(unaryInterceptors(o.buf, t.metricProvider)))
+
+	if !t.cfg.Traces.EnableRetry {
+		traceRetryOffOpt := otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{})
+		opts = append([]otlptracegrpc.Option{traceRetryOffOpt}, opts...)
+	}
+
+	return otlptracegrpc.NewClient(opts...), nil
 }
 
-func (o *oMetric) apply(ctx context.Context, t *Telemetry) func(context.Context) {
-	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(t.cfg.OtelConfig.Addr)}
+// httpClient sends traces as http/protobuf, upstream exporter has no json marshaller
+func (o *oTrace) httpClient(t *Telemetry) (otlptrace.Client, error) {
+	s, err := t.cfg.OtelConfig.exportSettings(t.cfg.Traces.Export)
+	if err != nil {
+		return nil, errors.WithMessage(err, "traces export settings")
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(s.endpoint)}
 
-	if t.cfg.OtelConfig.WithInsecure {
-		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	if s.urlPath != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(s.urlPath))
 	}
 
-	if t.cfg.OtelConfig.WithCompression {
-		opts = append(opts, otlpmetricgrpc.WithCompressor("gzip"))
+	if s.insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
 	}
 
-	if t.cfg.OtelConfig.IsTLS() {
-		cred, err := t.cfg.OtelConfig.createClientTLSCredentials()
-		handleErr(err, "Failed init TLS certificate")
+	if s.compression {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
 
-		if err == nil {
-			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(cred))
-		}
+	if len(s.headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(s.headers))
 	}
 
-	if !t.cfg.Metrics.EnableRetry {
-		metricRetryOff := otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{})
-		opts = append([]otlpmetricgrpc.Option{metricRetryOff}, opts...)
+	if s.tls != nil {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(s.tls))
+	}
+
+	if !t.cfg.Traces.EnableRetry {
+		traceRetryOffOpt := otlptracehttp.WithRetry(otlptracehttp.RetryConfig{})
+		opts = append([]otlptracehttp.Option{traceRetryOffOpt}, opts...)
 	}
 
-	exp, err := otlpmetricgrpc.New(ctx, opts...)
-	handleErr(err, "Faild create grpc metric client")
+	return selfmetric.TraceClient(otlptracehttp.NewClient(opts...), t.metricProvider), nil
+}
 
-	reader := metric.NewPeriodicReader(exp,
-		//metric.WithTimeout(30*time.Second),
-		metric.WithInterval(time.Duration(t.cfg.OtelConfig.MetricsPeriodicIntervalSec)*time.Second),
-	)
+type oMetric struct {
+	res *resource.Resource
 
-	var views []metric.View
-
-	for _, opt := range t.cfg.OtelConfig.bucketView {
-		// View to customize histogram buckets and rename a single histogram instrument.
-		customBucketsView := metric.NewView(
-			// Match* to match instruments
-			metric.Instrument{
-				Name: opt.MetricName,
-			},
-			//view.MatchInstrumentationScope(instrumentation.Scope{Name: meterName}),
-
-			// With* to modify instruments
-			metric.Stream{
-				Aggregation: metric.AggregationExplicitBucketHistogram{
-					Boundaries: opt.Bucket,
-				},
-			},
-			//view.WithRename("bar"),
-		)
+	// selfMetrics is provider of exporter self-metrics, the exporter is created before meter provider
+	selfMetrics *selfmetric.Deferred
 
-		handleErr(err, "creation histogram view")
+	// buf is on-disk buffer of exporter, closed on shutdown
+	buf *wal.WAL
+}
 
-		views = append(views, customBucketsView)
+func withOtelMetric(res *resource.Resource) controllers {
+	return &oMetric{res: res, selfMetrics: &selfmetric.Deferred{}}
+}
+
+func (o *oMetric) apply(ctx context.Context, t *Telemetry) (func(context.Context) error, error) {
+	readers, err := o.readers(ctx, t)
+	if err != nil {
+		return nil, err
 	}
 
-	// Default view to keep all instruments
-	defaultView := metric.NewView(metric.Instrument{Name: "*"}, metric.Stream{})
-	handleErr(err, "creation default view")
-	views = append(views, defaultView)
+	// provider works without exemplars, runtime and host metrics, so errors are returned along with shutdown
+	var errs error
+
+	// unknown mode works as drop, unknown estimator and hll in drop mode as exact
+	if mode := t.cfg.Metrics.CardinalityDetector.Mode; mode != "" {
+		errs = multierr.Append(errs, errors.WithMessage(cardinalitydetector.ValidateMode(mode), "metrics cardinality detector"))
+	}
+
+	if estimator := t.cfg.Metrics.CardinalityDetector.Estimator; estimator != "" {
+		errs = multierr.Append(errs, errors.WithMessage(cardinalitydetector.ValidateEstimator(estimator,
+			t.cfg.Metrics.CardinalityDetector.Mode), "metrics cardinality detector"))
+	}
+
+	// invalid env rules are skipped, code rules still apply
+	rules, err := cardinalityRules(t.cfg.Metrics.CardinalityDetector.Rules,
+		t.cfg.Metrics.CardinalityDetector.RulesFile, t.cfg.Metrics.CardinalityDetector.rules)
+	if err != nil {
+		errs = multierr.Append(errs, errors.WithMessage(err, "metrics cardinality detector"))
+		rules = t.cfg.Metrics.CardinalityDetector.rules
+	}
+
+	// invalid env views are skipped, WithHistogram ones still apply
+	views, err := t.cfg.OtelConfig.metricViews()
+	if err != nil {
+		errs = multierr.Append(errs, errors.WithMessage(err, "metric views"))
+		views = t.cfg.OtelConfig.histogramViews()
+	}
+
+	// single view applies the first matching one, instruments matching none keep default stream
+	opts := append(readers, metric.WithResource(o.res), metric.WithView(metricview.New(views...)))
+
+	// empty filter keeps sdk default: trace_based
+	if name := t.cfg.Metrics.ExemplarFilter; name != "" {
+		filter, err := sdkmetric.ExemplarFilter(name)
+		if err != nil {
+			errs = multierr.Append(errs, errors.WithMessage(err, "exemplar filter"))
+		} else {
+			opts = append(opts, metric.WithExemplarFilter(filter))
+		}
+	}
 
 	meterProvider := sdkmetric.NewMeterProvider(
 		ctx,
@@ -274,28 +538,185 @@ func (o *oMetric) apply(ctx context.Context, t *Telemetry) func(context.Context)
 			cardinalitydetector.WithMaxCardinality(t.cfg.Metrics.CardinalityDetector.MaxCardinality),
 			cardinalitydetector.WithMaxInstruments(t.cfg.Metrics.CardinalityDetector.MaxInstruments),
 			cardinalitydetector.WithCheckInterval(t.cfg.Metrics.CardinalityDetector.DiagnosticInterval),
+			cardinalitydetector.WithMode(t.cfg.Metrics.CardinalityDetector.Mode),
+			cardinalitydetector.WithEstimator(t.cfg.Metrics.CardinalityDetector.Estimator),
+			cardinalitydetector.WithWindow(t.cfg.Metrics.CardinalityDetector.Window),
+			cardinalitydetector.WithRules(rules...),
 		),
-		metric.WithReader(reader),
-		metric.WithResource(o.res),
-		metric.WithView(views...),
+		opts...,
 	)
 
 	otel.SetMeterProvider(meterProvider)
 	t.metricProvider = meterProvider
+	o.selfMetrics.Set(meterProvider)
+
+	// cardinality gauges go to the sdk provider, they aren't limited by detector
+	if err = o.cardinalityMetrics(t, meterProvider); err != nil {
+		errs = multierr.Append(errs, errors.WithMessage(err, "register cardinality metrics"))
+	}
 
 	// runtime exported
-	err = rt.Start()
-	handleErr(err, "Failed to start runtime metric")
+	if err = rt.Start(); err != nil {
+		errs = multierr.Append(errs, errors.WithMessage(err, "start runtime metric"))
+	}
 
 	// host metrics exporter
-	err = host.Start()
-	handleErr(err, "Failed to start host metric")
+	if err = host.Start(); err != nil {
+		errs = multierr.Append(errs, errors.WithMessage(err, "start host metric"))
+	}
 
-	return func(cxt context.Context) {
+	return func(cxt context.Context) error {
 		// pushes any last exports to the receiver
-		handleErr(meterProvider.Shutdown(cxt), "metric provider shutdown")
+		if err := meterProvider.Shutdown(cxt); err != nil {
+			return multierr.Append(errors.WithMessage(err, "metric provider shutdown"), closeBuffer(o.buf))
+		}
+
 		t.Info("OTEL metric provider has been shutdown")
+
+		return closeBuffer(o.buf)
+	}, errs
+}
+
+func (o *oMetric) cardinalityMetrics(t *Telemetry, meterProvider *sdkmetric.MeterProvider) error {
+	if !t.cfg.Metrics.CardinalityDetector.Enable || !t.cfg.Metrics.CardinalityDetector.Gauges {
+		return nil
+	}
+
+	return cardinalitydetector.RegisterMetrics(meterProvider.MeterProvider, selfmetric.SignalMetrics, meterProvider)
+}
+
+// readers are periodic OTLP reader, prometheus reader and injected ones, all see instruments wrapped by cardinality detector
+func (o *oMetric) readers(ctx context.Context, t *Telemetry) ([]metric.Option, error) {
+	var readers []metric.Option
+
+	if t.cfg.Metrics.Enable {
+		exp, err := o.exporter(ctx, t)
+		if err != nil {
+			return nil, errors.WithMessage(err, "create metric client")
+		}
+
+		readers = append(readers, metric.WithReader(metric.NewPeriodicReader(exp,
+			//metric.WithTimeout(30*time.Second),
+			metric.WithInterval(time.Duration(t.cfg.OtelConfig.MetricsPeriodicIntervalSec)*time.Second),
+		)))
+	}
+
+	if t.cfg.Metrics.Prometheus {
+		registry := prometheus.NewRegistry()
+
+		reader, err := otelprom.New(otelprom.WithRegisterer(registry))
+		if err != nil {
+			return nil, errors.WithMessage(err, "create prometheus reader")
+		}
+
+		readers = append(readers, metric.WithReader(reader))
+		t.metricsHandler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	}
+
+	for _, reader := range t.cfg.metricReaders {
+		readers = append(readers, metric.WithReader(reader))
 	}
+
+	return readers, nil
+}
+
+func (o *oMetric) exporter(ctx context.Context, t *Telemetry) (metric.Exporter, error) {
+	if err := t.cfg.OtelConfig.validateProtobufProtocol(); err != nil {
+		return nil, err
+	}
+
+	if t.cfg.OtelConfig.IsHTTP() {
+		opts, err := o.httpOptions(t)
+		if err != nil {
+			return nil, err
+		}
+
+		exp, err := otlpmetrichttp.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return selfmetric.MetricExporter(exp, o.selfMetrics), nil
+	}
+
+	opts, err := o.grpcOptions(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+func (o *oMetric) grpcOptions(t *Telemetry) ([]otlpmetricgrpc.Option, error) {
+	s, err := t.cfg.OtelConfig.exportSettings(t.cfg.Metrics.Export)
+	if err != nil {
+		return nil, errors.WithMessage(err, "metrics export settings")
+	}
+
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(s.endpoint)}
+
+	if s.insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+
+	if s.compression {
+		opts = append(opts, otlpmetricgrpc.WithCompressor("gzip"))
+	}
+
+	if len(s.headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(s.headers))
+	}
+
+	if s.tls != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(s.tls)))
+	}
+
+	o.buf = openBuffer(t, selfmetric.SignalMetrics)
+	opts = append(opts, otlpmetricgrpc.WithDialOption(unaryInterceptors(o.buf, o.selfMetrics)))
+
+	if !t.cfg.Metrics.EnableRetry {
+		metricRetryOff := otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{})
+		opts = append([]otlpmetricgrpc.Option{metricRetryOff}, opts...)
+	}
+
+	return opts, nil
+}
+
+// httpOptions sends metrics as http/protobuf, upstream exporter has no json marshaller
+func (o *oMetric) httpOptions(t *Telemetry) ([]otlpmetrichttp.Option, error) {
+	s, err := t.cfg.OtelConfig.exportSettings(t.cfg.Metrics.Export)
+	if err != nil {
+		return nil, errors.WithMessage(err, "metrics export settings")
+	}
+
+	opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(s.endpoint)}
+
+	if s.urlPath != "" {
+		opts = append(opts, otlpmetrichttp.WithURLPath(s.urlPath))
+	}
+
+	if s.insecure {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+
+	if s.compression {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+
+	if len(s.headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(s.headers))
+	}
+
+	if s.tls != nil {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(s.tls))
+	}
+
+	if !t.cfg.Metrics.EnableRetry {
+		metricRetryOff := otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{})
+		opts = append([]otlpmetrichttp.Option{metricRetryOff}, opts...)
+	}
+
+	return opts, nil
 }
 
 type oMonitor struct{}
@@ -306,9 +727,9 @@ func withMonitor() controllers {
 	return &oMonitor{}
 }
 
-func (o *oMonitor) apply(ctx context.Context, t *Telemetry) func(context.Context) {
+func (o *oMonitor) apply(ctx context.Context, t *Telemetry) (func(context.Context) error, error) {
 	if !t.cfg.MonitorConfig.Enable {
-		return func(ctx context.Context) {}
+		return nil, nil
 	}
 
 	t.Info("start monitoring", String("addr", t.cfg.MonitorAddr), Bool("debug", t.cfg.Debug))
@@ -317,6 +738,11 @@ func (o *oMonitor) apply(ctx context.Context, t *Telemetry) func(context.Context
 		monitoring.WithAddr(t.cfg.MonitorAddr),
 		monitoring.WithDebug(t.cfg.Debug),
 		monitoring.WithChecker(t.cfg.healthChecker...),
+		monitoring.WithLogLevels(t.levels),
+		monitoring.WithLogLevelWrite(t.cfg.MonitorConfig.LogLevelWrite),
+		monitoring.WithMetricsHandler(t.metricsHandler),
+		monitoring.WithMetricProvider(t.metricProvider),
+		monitoring.WithCardinality(cardinalityReporter(t.metricProvider), cardinalityReporter(t.traceProvider)),
 	)
 
 	go func() {
@@ -325,11 +751,18 @@ func (o *oMonitor) apply(ctx context.Context, t *Telemetry) func(context.Context
 		}
 	}()
 
-	return func(cxt context.Context) {
-		if err := m.GracefulStop(cxt); err != nil {
-			t.Error("stop monitoring", Error(err))
-		}
+	return func(cxt context.Context) error {
+		return errors.WithMessage(m.GracefulStop(cxt), "stop monitoring")
+	}, nil
+}
+
+// cardinalityReporter returns nil for providers without cardinality detection
+func cardinalityReporter(provider interface{}) cardinalitydetector.Reporter {
+	if r, ok := provider.(cardinalitydetector.Reporter); ok {
+		return r
 	}
+
+	return nil
 }
 
 // log wrapper
@@ -337,9 +770,9 @@ type logGrpc struct{}
 
 func withOtelClientLog() controllers { return &logGrpc{} }
 
-func (o *logGrpc) apply(_ context.Context, t *Telemetry) func(context.Context) {
+func (o *logGrpc) apply(_ context.Context, t *Telemetry) (func(context.Context) error, error) {
 	grpclog.SetLoggerV2(grpcerr.New(t.Logger))
-	return func(context.Context) {}
+	return nil, nil
 }
 
 // loggerOtel wrapper
@@ -347,10 +780,72 @@ type loggerOtel struct{}
 
 func withOtelProcessor() controllers { return &loggerOtel{} }
 
-func (o *loggerOtel) apply(_ context.Context, t *Telemetry) func(context.Context) {
+func (o *loggerOtel) apply(_ context.Context, t *Telemetry) (func(context.Context) error, error) {
 	adapterLog := otelerr.New(t.Logger)
 	otel.SetErrorHandler(adapterLog)
 	otel.SetLogger(logr.New(adapterLog))
 
-	return func(context.Context) {}
+	return nil, nil
+}
+
+// bufferSlots limits instances of service on the same host sharing default buffer dir
+const bufferSlots = 16
+
+// openBuffer returns on-disk buffer of signal exports, nil if buffering is disabled.
+// Default dir is stable per service: the first slot not locked by running instance is taken,
+// so restarted process replays entries of its predecessor.
+func openBuffer(t *Telemetry, signal string) *wal.WAL {
+	cfg := t.cfg.OtelConfig.Buffer
+	if !cfg.Enable || t.cfg.OtelConfig.IsHTTP() {
+		return nil
+	}
+
+	dir, slots := cfg.Dir, 1
+	if dir == "" {
+		dir, slots = filepath.Join(os.TempDir(), "tel", GenServiceName(t.cfg.Namespace, t.cfg.Service)), bufferSlots
+	}
+
+	var err error
+
+	for slot := 0; slot < slots; slot++ {
+		path := filepath.Join(dir, signal)
+		if slot > 0 {
+			path = filepath.Join(dir, strconv.Itoa(slot), signal)
+		}
+
+		var buf *wal.WAL
+		if buf, err = wal.Open(path, wal.WithMaxSize(cfg.MaxSize), wal.WithMaxAge(cfg.MaxAge)); err == nil {
+			return buf
+		}
+
+		if !errors.Is(err, wal.ErrLocked) {
+			break
+		}
+	}
+
+	t.Error("open export buffer", zap.String("signal", signal), zap.Error(err))
+
+	return nil
+}
+
+// closeBuffer closes buf after its exporter is shut down, nil buf is allowed
+func closeBuffer(buf *wal.WAL) error {
+	if buf == nil {
+		return nil
+	}
+
+	return errors.WithMessage(buf.Close(), "close export buffer")
+}
+
+// unaryInterceptors of exporter connection, buffer goes first so replayed requests are measured as well
+func unaryInterceptors(buf *wal.WAL, mp otelmetric.MeterProvider) grpc.DialOption {
+	var interceptors []grpc.UnaryClientInterceptor
+
+	if buf != nil {
+		interceptors = append(interceptors, wal.UnaryClientInterceptor(buf))
+	}
+
+	interceptors = append(interceptors, selfmetric.UnaryClientInterceptor(mp))
+
+	return grpc.WithChainUnaryInterceptor(interceptors...)
 }
@@ -0,0 +1,189 @@
+package tel
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrUnknownCompression = errors.New("unknown compression")
+
+// Compressions supported by OTEL_EXPORTER_OTLP_{LOGS,TRACES,METRICS}_COMPRESSION
+const (
+	CompressionGzip = "gzip"
+	CompressionNone = "none"
+)
+
+// Headers of export requests, env format is key1=value1,key2=value2 with url-encoded keys and values
+type Headers map[string]string
+
+// ExportConfig overrides collector connection of single signal, empty values fall back to common OtelConfig settings.
+// Env names follow OTEL_EXPORTER_OTLP_{LOGS,TRACES,METRICS}_* specification
+type ExportConfig struct {
+	// Endpoint is host:port or url of collector, http:// scheme makes connection insecure, https:// secure.
+	// URL path is used by http protocol only, for instance https://mimir.example.com/otlp/v1/metrics
+	Endpoint string `env:"ENDPOINT"`
+
+	// Insecure overrides OTEL_EXPORTER_WITH_INSECURE and scheme of endpoint
+	Insecure *bool `env:"INSECURE"`
+
+	// Headers are sent with each export request: key1=value1,key2=value2
+	Headers Headers `env:"HEADERS"`
+
+	// Compression of requests: gzip or none
+	Compression string `env:"COMPRESSION"`
+
+	// Certificate is path to PEM file of trusted CA, ClientCertificate and ClientKey are paths
+	// to PEM files of client key pair. Replace OTEL_COLLECTOR_TLS_* settings if any is set
+	Certificate       string `env:"CERTIFICATE"`
+	ClientCertificate string `env:"CLIENT_CERTIFICATE"`
+	ClientKey         string `env:"CLIENT_KEY"`
+}
+
+// exportSettings is collector connection of signal with applied fallbacks
+type exportSettings struct {
+	endpoint    string
+	urlPath     string
+	insecure    bool
+	compression bool
+	headers     map[string]string
+	// tls is nil without TLS configuration
+	tls *tls.Config
+}
+
+func (c *OtelConfig) exportSettings(e ExportConfig) (exportSettings, error) {
+	s := exportSettings{
+		endpoint:    c.Addr,
+		insecure:    c.WithInsecure,
+		compression: c.WithCompression,
+	}
+
+	if c.IsHTTP() {
+		s.endpoint = c.HTTPAddr
+	}
+
+	if e.Endpoint != "" {
+		if err := s.parseEndpoint(e.Endpoint); err != nil {
+			return s, err
+		}
+	}
+
+	if e.Insecure != nil {
+		s.insecure = *e.Insecure
+	}
+
+	switch strings.ToLower(e.Compression) {
+	case "":
+	case CompressionGzip:
+		s.compression = true
+	case CompressionNone:
+		s.compression = false
+	default:
+		return s, errors.WithMessagef(ErrUnknownCompression, "%q", e.Compression)
+	}
+
+	if len(c.Headers)+len(e.Headers) > 0 {
+		s.headers = make(map[string]string, len(c.Headers)+len(e.Headers))
+
+		for k, v := range c.Headers {
+			s.headers[k] = v
+		}
+
+		for k, v := range e.Headers {
+			s.headers[k] = v
+		}
+	}
+
+	var err error
+
+	switch {
+	case e.Certificate != "" || e.ClientCertificate != "" || e.ClientKey != "":
+		s.tls, err = e.tlsConfig(c.ServerName)
+	case c.IsTLS():
+		s.tls, err = c.createClientTLSConfig()
+	}
+
+	return s, errors.WithMessage(err, "init TLS certificate")
+}
+
+func (s *exportSettings) parseEndpoint(endpoint string) error {
+	if !strings.Contains(endpoint, "://") {
+		s.endpoint = endpoint
+		return nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return errors.WithMessagef(err, "parse endpoint %q", endpoint)
+	}
+
+	s.endpoint = u.Host
+	s.insecure = u.Scheme == "http"
+
+	if u.Path != "" && u.Path != "/" {
+		s.urlPath = u.Path
+	}
+
+	return nil
+}
+
+func (e ExportConfig) tlsConfig(serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{ServerName: serverName}
+
+	if e.ClientCertificate != "" || e.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(e.ClientCertificate, e.ClientKey)
+		if err != nil {
+			return nil, errors.WithMessage(err, "load key/pair")
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if e.Certificate != "" {
+		b, err := os.ReadFile(e.Certificate)
+		if err != nil {
+			return nil, errors.WithMessage(err, "read certificate")
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+
+		if !cfg.RootCAs.AppendCertsFromPEM(b) {
+			return nil, ErrCaAppend
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseHeaders(v string) (interface{}, error) {
+	h := make(Headers)
+
+	for _, pair := range strings.Split(v, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, errors.Errorf("header %q should be in key=value format", pair)
+		}
+
+		k, err := url.QueryUnescape(strings.TrimSpace(key))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "header key %q", key)
+		}
+
+		v, err := url.QueryUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "header %q value", k)
+		}
+
+		h[k] = v
+	}
+
+	return h, nil
+}
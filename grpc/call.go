@@ -0,0 +1,209 @@
+// Package grpc provides unary and stream interceptors which propagate trace context via metadata,
+// wrap calls in spans with Telemetry in context, record metrics and log call status.
+package grpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tel-io/tel/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const instrumentationName = "github.com/tel-io/tel/v2/grpc"
+
+const (
+	// MetricServerDuration is duration of handled calls in seconds
+	MetricServerDuration = "rpc.server.duration"
+	// MetricServerRequests counts handled calls by status code
+	MetricServerRequests = "rpc.server.requests"
+
+	// MetricClientDuration is duration of sent calls in seconds
+	MetricClientDuration = "rpc.client.duration"
+	// MetricClientRequests counts sent calls by status code
+	MetricClientRequests = "rpc.client.requests"
+)
+
+type measures struct {
+	duration metric.Float64Histogram
+	requests metric.Int64Counter
+}
+
+func newMeasures(mp metric.MeterProvider, duration, requests string) measures {
+	meter := mp.Meter(instrumentationName)
+
+	var (
+		m   measures
+		err error
+	)
+
+	m.duration, err = meter.Float64Histogram(duration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of calls"))
+	handleErr(err)
+
+	m.requests, err = meter.Int64Counter(requests,
+		metric.WithDescription("Number of calls"))
+	handleErr(err)
+
+	return m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// call is instrumented rpc in progress
+type call struct {
+	o    Options
+	m    measures
+	kind trace.SpanKind
+
+	name  string
+	start time.Time
+	span  trace.Span
+	ctx   context.Context
+	attrs []attribute.KeyValue
+
+	once sync.Once
+}
+
+func startCall(ctx context.Context, o Options, m measures, kind trace.SpanKind, fullMethod string) *call {
+	tele := o.Tel()
+	if t := tel.ContextValue(ctx); t != nil {
+		tele = t.Copy()
+	}
+
+	name, attrs := spanInfo(fullMethod)
+
+	span, ctx := tele.StartSpan(ctx, name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(attrs...),
+	)
+
+	return &call{
+		o:     o,
+		m:     m,
+		kind:  kind,
+		name:  name,
+		start: time.Now(),
+		span:  span,
+		ctx:   ctx,
+		attrs: attrs,
+	}
+}
+
+// end finishes call once, following calls are ignored
+// endPanic finishes call of panicked handler as Internal, panic is recorded on span with stack trace
+func (c *call) endPanic(rec interface{}) {
+	c.end(status.Errorf(codes.Internal, "panic: %v", rec), trace.WithStackTrace(true))
+}
+
+func (c *call) end(err error, opts ...trace.EventOption) {
+	c.once.Do(func() {
+		s, _ := status.FromError(err)
+		code := s.Code()
+		duration := time.Since(c.start)
+
+		c.span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+
+		if isError(c.kind, code) {
+			c.span.RecordError(err, opts...)
+			c.span.SetStatus(otelcodes.Error, s.Message())
+		}
+
+		c.span.End()
+
+		attrs := metric.WithAttributes(append(c.attrs, semconv.RPCGRPCStatusCodeKey.Int(int(code)))...)
+		c.m.duration.Record(c.ctx, duration.Seconds(), attrs)
+		c.m.requests.Add(c.ctx, 1, attrs)
+
+		if !c.o.Log {
+			return
+		}
+
+		msg := "grpc server: finished call"
+		if c.kind == trace.SpanKindClient {
+			msg = "grpc client: finished call"
+		}
+
+		fields := []zap.Field{
+			tel.String("method", c.name),
+			tel.String("code", code.String()),
+			tel.Duration("duration", duration),
+		}
+		if err != nil {
+			fields = append(fields, tel.Error(err))
+		}
+
+		tel.FromCtx(c.ctx).Log(c.o.CodeLevel(code), msg, fields...)
+	})
+}
+
+// isError follows semantic conventions: server spans are failed only with server faults
+func isError(kind trace.SpanKind, code codes.Code) bool {
+	if kind == trace.SpanKindClient {
+		return code != codes.OK
+	}
+
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// spanInfo returns span name and rpc attributes of full method name like /package.Service/Method
+func spanInfo(fullMethod string) (string, []attribute.KeyValue) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	attrs := []attribute.KeyValue{semconv.RPCSystemKey.String("grpc")}
+
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		attrs = append(attrs,
+			semconv.RPCServiceKey.String(name[:i]),
+			semconv.RPCMethodKey.String(name[i+1:]),
+		)
+	}
+
+	return name, attrs
+}
+
+// metadataCarrier adapts metadata.MD to propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	v := metadata.MD(c).Get(key)
+	if len(v) == 0 {
+		return ""
+	}
+
+	return v[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	out := make([]string, 0, len(c))
+	for k := range c {
+		out = append(out, k)
+	}
+
+	return out
+}
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor wraps call in span and injects trace context into outgoing metadata
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := NewOptions(opts...)
+	m := newMeasures(o.Meters(), MetricClientDuration, MetricClientRequests)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if !o.Filter(method) {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+
+		c := startCall(ctx, o, m, trace.SpanKindClient, method)
+
+		err := invoker(inject(c.ctx, o), method, req, reply, cc, callOpts...)
+		c.end(err)
+
+		return err
+	}
+}
+
+// StreamClientInterceptor wraps stream in span and injects trace context into outgoing metadata.
+// Span ends when stream receives io.EOF or error
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := NewOptions(opts...)
+	m := newMeasures(o.Meters(), MetricClientDuration, MetricClientRequests)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !o.Filter(method) {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+
+		c := startCall(ctx, o, m, trace.SpanKindClient, method)
+
+		cs, err := streamer(inject(c.ctx, o), desc, cc, method, callOpts...)
+		if err != nil {
+			c.end(err)
+			return nil, err
+		}
+
+		return &clientStream{ClientStream: cs, call: c, serverStreams: desc.ServerStreams}, nil
+	}
+}
+
+func inject(ctx context.Context, o Options) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	o.Propagator.Inject(ctx, metadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// clientStream finishes call when stream is over
+type clientStream struct {
+	grpc.ClientStream
+
+	call          *call
+	serverStreams bool
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	switch {
+	case errors.Is(err, io.EOF):
+		s.call.end(nil)
+	case err != nil:
+		s.call.end(err)
+	case !s.serverStreams:
+		// unary response of client stream means stream is over
+		s.call.end(nil)
+	}
+
+	return err
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.call.end(err)
+	}
+
+	return err
+}
+
+func (s *clientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.call.end(err)
+	}
+
+	return md, err
+}
@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2"
+	"github.com/tel-io/tel/v2/teltest"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	checkMethod = "grpc.health.v1.Health/Check"
+	watchMethod = "grpc.health.v1.Health/Watch"
+)
+
+type healthServer struct {
+	*health.Server
+}
+
+func (s healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	tel.FromCtx(ctx).Info("check")
+
+	return s.Server.Check(ctx, req)
+}
+
+func dial(t *testing.T) healthpb.HealthClient {
+	t.Helper()
+
+	prop := WithPropagator(propagation.TraceContext{})
+
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(prop)),
+		grpc.StreamInterceptor(StreamServerInterceptor(prop)),
+	)
+	healthpb.RegisterHealthServer(srv, healthServer{Server: health.NewServer()})
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(prop)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(prop)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	h := teltest.New(t)
+	client := dial(t)
+
+	_, err := client.Check(h.Ctx(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	_, err = client.Check(h.Ctx(), &healthpb.HealthCheckRequest{Service: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	spans := h.Spans().Name(checkMethod)
+	require.Len(t, spans, 4)
+
+	trees := spans.Trees()
+	require.Len(t, trees, 2)
+	for _, tree := range trees {
+		require.Len(t, tree.Children, 1, "server span is child of client span")
+	}
+
+	t.Run("handler logs within span", func(t *testing.T) {
+		logs := h.Logs().Message("check")
+		require.Len(t, logs, 2)
+		assert.True(t, logs[0].SpanID.IsValid())
+	})
+
+	t.Run("status logs", func(t *testing.T) {
+		logs := h.Logs().Message("grpc server: finished call")
+		require.Len(t, logs, 2)
+		assert.Len(t, logs.With(attribute.String("code", codes.NotFound.String())).Level(zapcore.InfoLevel), 1)
+		assert.Len(t, h.Logs().Message("grpc client: finished call"), 2)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		m := h.Metrics()
+
+		v, ok := m.Int64(MetricServerRequests,
+			attribute.String("rpc.method", "Check"), attribute.Int("rpc.grpc.status_code", int(codes.NotFound)))
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), v)
+
+		count, ok := m.HistogramCount(MetricClientDuration, attribute.String("rpc.service", "grpc.health.v1.Health"))
+		assert.True(t, ok)
+		assert.Equal(t, uint64(2), count)
+	})
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	h := teltest.New(t)
+	client := dial(t)
+
+	ctx, cancel := context.WithCancel(h.Ctx())
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+
+	cancel()
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+
+	assert.Eventually(t, func() bool {
+		return len(h.Spans().Name(watchMethod)) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	v, ok := h.Metrics().Int64(MetricClientRequests, attribute.Int("rpc.grpc.status_code", int(codes.Canceled)))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), v)
+}
+
+func TestServerInterceptors_Panic(t *testing.T) {
+	h := teltest.New(t)
+
+	unary := UnaryServerInterceptor(WithLog(false))
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = unary(h.Ctx(), nil, &grpc.UnaryServerInfo{FullMethod: "/" + checkMethod},
+			func(context.Context, interface{}) (interface{}, error) { panic("boom") })
+	})
+
+	stream := StreamServerInterceptor(WithLog(false))
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = stream(nil, &serverStream{ctx: h.Ctx()}, &grpc.StreamServerInfo{FullMethod: "/" + watchMethod},
+			func(interface{}, grpc.ServerStream) error { panic("boom") })
+	})
+
+	for _, method := range []string{checkMethod, watchMethod} {
+		span := h.Spans().Name(method).First()
+		require.NotNil(t, span, method)
+		assert.Equal(t, otelcodes.Error, span.Status().Code)
+		require.Len(t, span.Events(), 1)
+		assert.Contains(t, span.Events()[0].Attributes, attribute.String("exception.message", "rpc error: code = Internal desc = panic: boom"))
+	}
+
+	count, ok := h.Metrics().HistogramCount(MetricServerDuration, attribute.Int("rpc.grpc.status_code", int(codes.Internal)))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), count)
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"github.com/tel-io/tel/v2"
+	"github.com/tel-io/tel/v2/internal/instrumentation"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// FilterFunc returns false for full method names which should not be instrumented
+type FilterFunc func(fullMethod string) bool
+
+// CodeLevelFunc returns level of log line written for finished call
+type CodeLevelFunc func(code codes.Code) zapcore.Level
+
+type Option func(*Options)
+
+func DefaultOptions() Options {
+	return Options{
+		Propagator: otel.GetTextMapPropagator(),
+		Log:        true,
+		CodeLevel:  DefaultCodeLevel,
+		Filter:     func(string) bool { return true },
+	}
+}
+
+func NewOptions(options ...Option) Options {
+	return instrumentation.Apply(DefaultOptions(), options...)
+}
+
+type Options struct {
+	instrumentation.Options
+
+	Propagator propagation.TextMapPropagator
+	Log        bool
+	CodeLevel  CodeLevelFunc
+	Filter     FilterFunc
+}
+
+// DefaultCodeLevel logs client faults with info, server faults with error
+// and transient issues with warn level
+func DefaultCodeLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.Unauthenticated:
+		return zapcore.InfoLevel
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+func WithTel(t *tel.Telemetry) Option {
+	return instrumentation.WithTel[Options](t)
+}
+
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return instrumentation.WithMeterProvider[Options](mp)
+}
+
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(opts *Options) {
+		opts.Propagator = p
+	}
+}
+
+// WithLog enables log line of finished call
+func WithLog(b bool) Option {
+	return func(opts *Options) {
+		opts.Log = b
+	}
+}
+
+// WithCodeLevel sets level of log line of finished call by status code
+func WithCodeLevel(fn CodeLevelFunc) Option {
+	return func(opts *Options) {
+		opts.CodeLevel = fn
+	}
+}
+
+// WithFilter skips instrumentation of methods for which fn returns false
+func WithFilter(fn FilterFunc) Option {
+	return func(opts *Options) {
+		opts.Filter = fn
+	}
+}
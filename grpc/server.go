@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerInterceptor extracts trace context from incoming metadata and runs handler
+// within span with Telemetry in context
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := NewOptions(opts...)
+	m := newMeasures(o.Meters(), MetricServerDuration, MetricServerRequests)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if !o.Filter(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		c := startCall(extract(ctx, o), o, m, trace.SpanKindServer, info.FullMethod)
+
+		// panic is measured and passed on, recovery is up to grpc.Server interceptors
+		defer func() {
+			if rec := recover(); rec != nil {
+				c.endPanic(rec)
+				panic(rec)
+			}
+		}()
+
+		resp, err := handler(c.ctx, req)
+		c.end(err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor extracts trace context from incoming metadata and runs handler
+// within span with Telemetry in stream context
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := NewOptions(opts...)
+	m := newMeasures(o.Meters(), MetricServerDuration, MetricServerRequests)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if !o.Filter(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		c := startCall(extract(ss.Context(), o), o, m, trace.SpanKindServer, info.FullMethod)
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				c.endPanic(rec)
+				panic(rec)
+			}
+		}()
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: c.ctx})
+		c.end(err)
+
+		return err
+	}
+}
+
+func extract(ctx context.Context, o Options) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	return o.Propagator.Extract(ctx, metadataCarrier(md))
+}
+
+// serverStream overrides context of stream
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
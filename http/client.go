@@ -0,0 +1,108 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tel-io/tel/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// Transport is http.RoundTripper which starts client span, injects trace context
+// into request headers and records client metrics
+type Transport struct {
+	base http.RoundTripper
+	opts Options
+	m    measures
+}
+
+// NewTransport wraps base, http.DefaultTransport if nil
+func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	o := NewOptions(opts...)
+
+	return &Transport{
+		base: base,
+		opts: o,
+		m: newMeasures(o.Meters(),
+			MetricClientRequests, MetricClientDuration, MetricClientActiveRequests),
+	}
+}
+
+// NewClient returns copy of c with instrumented transport, http.DefaultClient is used if c is nil
+func NewClient(c *http.Client, opts ...Option) *http.Client {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	out := *c
+	out.Transport = NewTransport(c.Transport, opts...)
+
+	return &out
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !t.opts.Filter(r) {
+		return t.base.RoundTrip(r)
+	}
+
+	start := time.Now()
+	ctx := r.Context()
+
+	tele := t.opts.Tel()
+	if v := tel.ContextValue(ctx); v != nil {
+		tele = v.Copy()
+	}
+
+	span, ctx := tele.StartSpan(ctx, "HTTP "+r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPURLKey.String(r.URL.Redacted()),
+			semconv.NetPeerNameKey.String(r.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(r.Method),
+		semconv.NetPeerNameKey.String(r.URL.Hostname()),
+	}
+
+	inFlight := metric.WithAttributes(attrs...)
+	t.m.active.Add(ctx, 1, inFlight)
+	defer t.m.active.Add(ctx, -1, inFlight)
+
+	// RoundTripper must not modify the request
+	r = r.Clone(ctx)
+	t.opts.Propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	res, err := t.base.RoundTrip(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		attrs = append(attrs, semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+
+		if c, desc := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(res.StatusCode, trace.SpanKindClient); c == codes.Error {
+			span.SetStatus(c, desc)
+		}
+	}
+
+	t.m.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+	t.m.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+
+	return res, err
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "github.com/tel-io/tel/v2/http"
+
+const (
+	// MetricServerRequests counts handled requests
+	MetricServerRequests = "http.server.requests"
+	// MetricServerDuration is duration of handled requests in seconds
+	MetricServerDuration = "http.server.request.duration"
+	// MetricServerActiveRequests is number of requests in flight
+	MetricServerActiveRequests = "http.server.active_requests"
+
+	// MetricClientRequests counts sent requests
+	MetricClientRequests = "http.client.requests"
+	// MetricClientDuration is duration of sent requests in seconds
+	MetricClientDuration = "http.client.request.duration"
+	// MetricClientActiveRequests is number of requests in flight
+	MetricClientActiveRequests = "http.client.active_requests"
+)
+
+type measures struct {
+	requests metric.Int64Counter
+	duration metric.Float64Histogram
+	active   metric.Int64UpDownCounter
+}
+
+func newMeasures(mp metric.MeterProvider, requests, duration, active string) measures {
+	meter := mp.Meter(instrumentationName)
+
+	var (
+		m   measures
+		err error
+	)
+
+	m.requests, err = meter.Int64Counter(requests,
+		metric.WithDescription("Number of requests"))
+	handleErr(err)
+
+	m.duration, err = meter.Float64Histogram(duration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of requests"))
+	handleErr(err)
+
+	m.active, err = meter.Int64UpDownCounter(active,
+		metric.WithDescription("Number of requests in flight"))
+	handleErr(err)
+
+	return m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tel-io/tel/v2"
+	"github.com/tel-io/tel/v2/internal/instrumentation"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// RouteFunc returns route template of request, empty result means route is unknown.
+// Default one always returns empty route: set WithServeMux, WithRoute or call SetRoute in handler
+type RouteFunc func(r *http.Request) string
+
+// FilterFunc returns false for requests which should not be instrumented
+type FilterFunc func(r *http.Request) bool
+
+type Option func(*Options)
+
+func DefaultOptions() Options {
+	return Options{
+		Propagator: otel.GetTextMapPropagator(),
+		AccessLog:  true,
+		Route:      func(*http.Request) string { return "" },
+		Filter:     func(*http.Request) bool { return true },
+	}
+}
+
+func NewOptions(options ...Option) Options {
+	return instrumentation.Apply(DefaultOptions(), options...)
+}
+
+type Options struct {
+	instrumentation.Options
+
+	Propagator propagation.TextMapPropagator
+	AccessLog  bool
+	Route      RouteFunc
+	Filter     FilterFunc
+}
+
+func WithTel(t *tel.Telemetry) Option {
+	return instrumentation.WithTel[Options](t)
+}
+
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return instrumentation.WithMeterProvider[Options](mp)
+}
+
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(opts *Options) {
+		opts.Propagator = p
+	}
+}
+
+// WithAccessLog enables access log lines of server middleware
+func WithAccessLog(b bool) Option {
+	return func(opts *Options) {
+		opts.AccessLog = b
+	}
+}
+
+// WithRoute sets function returning route template of request, by default route is unknown
+// and requests are measured as UnknownRoute. Handlers are able to override it with SetRoute
+func WithRoute(fn RouteFunc) Option {
+	return func(opts *Options) {
+		opts.Route = fn
+	}
+}
+
+// WithServeMux takes route template from pattern registered in mux, method of pattern is omitted
+func WithServeMux(mux *http.ServeMux) Option {
+	return WithRoute(func(r *http.Request) string {
+		_, pattern := mux.Handler(r)
+		if i := strings.IndexByte(pattern, ' '); i >= 0 {
+			pattern = strings.TrimLeft(pattern[i:], " ")
+		}
+
+		return pattern
+	})
+}
+
+// WithFilter skips instrumentation of requests for which fn returns false
+func WithFilter(fn FilterFunc) Option {
+	return func(opts *Options) {
+		opts.Filter = fn
+	}
+}
@@ -0,0 +1,172 @@
+// Package http instruments net/http servers and clients with Telemetry:
+// spans, RED metrics and access logs.
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tel-io/tel/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// UnknownRoute is route template of requests without known route, raw paths are never used
+// to keep cardinality of metrics low
+const UnknownRoute = "unknown"
+
+type routeKey struct{}
+
+type routeHolder struct {
+	route string
+}
+
+// SetRoute overrides route template of current request, useful for routers which resolve
+// templates only after middleware is called
+func SetRoute(ctx context.Context, route string) {
+	if h, ok := ctx.Value(routeKey{}).(*routeHolder); ok {
+		h.route = route
+	}
+}
+
+// ServerMiddleware starts span for each request via Telemetry.StartSpan, puts request-scoped
+// Telemetry into request context, records RED metrics and writes access log
+func ServerMiddleware(opts ...Option) func(http.Handler) http.Handler {
+	o := NewOptions(opts...)
+	m := newMeasures(o.Meters(),
+		MetricServerRequests, MetricServerDuration, MetricServerActiveRequests)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !o.Filter(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			serve(o, m, next, w, r)
+		})
+	}
+}
+
+// Handler wraps h with ServerMiddleware using fixed route template
+func Handler(route string, h http.Handler, opts ...Option) http.Handler {
+	opts = append(opts, WithRoute(func(*http.Request) string { return route }))
+
+	return ServerMiddleware(opts...)(h)
+}
+
+func serve(o Options, m measures, next http.Handler, w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	ctx := o.Propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	tele := o.Tel()
+	if t := tel.ContextValue(ctx); t != nil {
+		tele = t.Copy()
+	}
+
+	holder := &routeHolder{route: o.Route(r)}
+	ctx = context.WithValue(ctx, routeKey{}, holder)
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(r.Method),
+		semconv.HTTPTargetKey.String(r.URL.Path),
+		semconv.HTTPUserAgentKey.String(r.UserAgent()),
+	}
+
+	// route known at start lets sampler rules match it
+	if holder.route != "" {
+		attrs = append(attrs, semconv.HTTPRouteKey.String(holder.route))
+	}
+
+	span, ctx := tele.StartSpan(ctx, spanName(r.Method, holder.route),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+
+	inFlight := metric.WithAttributes(semconv.HTTPMethodKey.String(r.Method))
+	m.active.Add(ctx, 1, inFlight)
+	defer m.active.Add(ctx, -1, inFlight)
+
+	rw := newResponseWriter(w)
+
+	defer func() {
+		// panic is measured and passed on to http.Server, http.ErrAbortHandler is its way to abort response
+		rec := recover()
+		if rec != nil && rec != http.ErrAbortHandler { //nolint:errorlint // sentinel is compared as net/http does
+			span.RecordError(fmt.Errorf("panic: %v", rec), trace.WithStackTrace(true))
+			rw.status = http.StatusInternalServerError
+		}
+
+		route := holder.route
+		if route == "" {
+			route = UnknownRoute
+		}
+
+		span.SetName(spanName(r.Method, route))
+		span.SetAttributes(semconv.HTTPRouteKey.String(route), semconv.HTTPStatusCodeKey.Int(rw.status))
+
+		if c, desc := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(rw.status, trace.SpanKindServer); c == codes.Error {
+			span.SetStatus(c, desc)
+		}
+
+		attrs := metric.WithAttributes(
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPRouteKey.String(route),
+			semconv.HTTPStatusCodeKey.Int(rw.status),
+		)
+
+		duration := time.Since(start)
+		m.requests.Add(ctx, 1, attrs)
+		m.duration.Record(ctx, duration.Seconds(), attrs)
+
+		if o.AccessLog {
+			accessLog(ctx, r, route, rw, duration)
+		}
+
+		// span is ended here rather than deferred, otherwise sdk records panic once again
+		span.End()
+
+		if rec != nil {
+			panic(rec)
+		}
+	}()
+
+	next.ServeHTTP(rw, r.WithContext(ctx))
+}
+
+func accessLog(ctx context.Context, r *http.Request, route string, rw *responseWriter, duration time.Duration) {
+	lvl := zapcore.InfoLevel
+
+	switch {
+	case rw.status >= http.StatusInternalServerError:
+		lvl = zapcore.ErrorLevel
+	case rw.status >= http.StatusBadRequest:
+		lvl = zapcore.WarnLevel
+	}
+
+	tel.FromCtx(ctx).Log(lvl, "http server: access",
+		tel.String("method", r.Method),
+		tel.String("route", route),
+		tel.String("path", r.URL.Path),
+		tel.Int("status", rw.status),
+		tel.Int64("size", rw.size),
+		tel.Duration("duration", duration),
+		tel.String("remote_addr", r.RemoteAddr),
+	)
+}
+
+func spanName(method, route string) string {
+	if route == "" {
+		return "HTTP " + method
+	}
+
+	return method + " " + route
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2"
+	"github.com/tel-io/tel/v2/teltest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestServerMiddleware(t *testing.T) {
+	h := teltest.New(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tel.FromCtx(r.Context()).Info("handler")
+		_, _ = io.WriteString(w, "ok")
+	})
+	mux.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+
+	srv := httptest.NewServer(ServerMiddleware(WithServeMux(mux))(mux))
+	defer srv.Close()
+
+	// fresh connection for each request, client retries idempotent request on reused connection
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	for _, path := range []string{"/users/1", "/users/2", "/panic", "/missing"} {
+		res, err := client.Get(srv.URL + path)
+		if path == "/panic" {
+			// panic is passed on to http.Server which drops connection
+			require.Error(t, err)
+			continue
+		}
+
+		require.NoError(t, err)
+		_ = res.Body.Close()
+	}
+
+	t.Run("spans", func(t *testing.T) {
+		spans := h.Spans()
+		assert.Len(t, spans.Name("GET /users/{id}"), 2)
+		assert.Len(t, spans.Name("GET /panic"), 1)
+		assert.Len(t, spans.Name("GET "+UnknownRoute), 1)
+	})
+
+	t.Run("handler logs within span", func(t *testing.T) {
+		logs := h.Logs().Message("handler")
+		require.Len(t, logs, 2)
+		assert.True(t, logs[0].SpanID.IsValid())
+	})
+
+	t.Run("access log", func(t *testing.T) {
+		access := h.Logs().Message("http server: access")
+		require.Len(t, access, 4)
+		assert.Len(t, access.With(attribute.String("route", "/users/{id}")), 2)
+		assert.Len(t, access.Level(zapcore.ErrorLevel), 1)
+		assert.Len(t, access.Level(zapcore.WarnLevel), 1)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		m := h.Metrics()
+
+		v, ok := m.Int64(MetricServerRequests,
+			attribute.String("http.route", "/users/{id}"), attribute.Int("http.status_code", 200))
+		assert.True(t, ok)
+		assert.Equal(t, int64(2), v)
+
+		v, ok = m.Int64(MetricServerRequests, attribute.Int("http.status_code", 500))
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), v)
+
+		count, ok := m.HistogramCount(MetricServerDuration)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(4), count)
+
+		v, ok = m.Int64(MetricServerActiveRequests)
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), v)
+	})
+}
+
+func TestSetRoute(t *testing.T) {
+	h := teltest.New(t)
+
+	handler := ServerMiddleware(WithAccessLog(false))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SetRoute(r.Context(), "/items/{id}")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/items/42", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Len(t, h.Spans().Name("DELETE /items/{id}"), 1)
+	assert.Empty(t, h.Logs())
+}
+
+func TestServerMiddleware_Panic(t *testing.T) {
+	h := teltest.New(t)
+
+	mw := ServerMiddleware(WithAccessLog(false), WithRoute(func(r *http.Request) string { return r.URL.Path }))
+
+	boom := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }))
+	assert.PanicsWithValue(t, "boom", func() {
+		boom.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+	})
+
+	abort := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic(http.ErrAbortHandler) }))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	})
+
+	spans := h.Spans()
+
+	panicked := spans.Name("GET /boom").First()
+	require.NotNil(t, panicked)
+	assert.Equal(t, codes.Error, panicked.Status().Code)
+	require.Len(t, panicked.Events(), 1)
+	assert.Equal(t, "exception", panicked.Events()[0].Name)
+
+	aborted := spans.Name("GET /abort").First()
+	require.NotNil(t, aborted)
+	assert.NotEqual(t, codes.Error, aborted.Status().Code)
+	assert.Empty(t, aborted.Events())
+}
+
+func TestTransport(t *testing.T) {
+	h := teltest.New(t)
+	prop := propagation.TraceContext{}
+
+	var remote trace.SpanContext
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remote = trace.SpanContextFromContext(prop.Extract(r.Context(), propagation.HeaderCarrier(r.Header)))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := NewClient(nil, WithPropagator(prop))
+
+	span, ctx := tel.FromCtx(h.Ctx()).StartSpan(h.Ctx(), "caller")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	span.End()
+
+	clientSpan := h.Spans().Name("HTTP GET").First()
+	require.NotNil(t, clientSpan)
+	assert.Equal(t, span.SpanContext().SpanID(), clientSpan.Parent().SpanID())
+	assert.Equal(t, clientSpan.SpanContext().SpanID(), remote.SpanID())
+
+	v, ok := h.Metrics().Int64(MetricClientRequests, attribute.Int("http.status_code", http.StatusNotFound))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), v)
+}
@@ -0,0 +1,66 @@
+// Package instrumentation holds options shared by http, grpc and pgx middlewares
+package instrumentation
+
+import (
+	"github.com/tel-io/tel/v2"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Options are embedded into options of middleware
+type Options struct {
+	// Telemetry is used when context holds no telemetry, tel.Global() if nil
+	Telemetry *tel.Telemetry
+	// MeterProvider of telemetry if nil
+	MeterProvider metric.MeterProvider
+}
+
+// Tel returns copy of Telemetry or global one
+func (o Options) Tel() tel.Telemetry {
+	if o.Telemetry != nil {
+		return o.Telemetry.Copy()
+	}
+
+	return tel.Global()
+}
+
+// Meters returns MeterProvider or the one of Tel
+func (o Options) Meters() metric.MeterProvider {
+	if o.MeterProvider != nil {
+		return o.MeterProvider
+	}
+
+	return o.Tel().MetricProvider()
+}
+
+func (o *Options) shared() *Options {
+	return o
+}
+
+// embedder is pointer to middleware options embedding Options
+type embedder[T any] interface {
+	*T
+	shared() *Options
+}
+
+// Apply applies options to defaults
+func Apply[T any, O ~func(*T)](defaults T, options ...O) T {
+	for _, opt := range options {
+		opt(&defaults)
+	}
+
+	return defaults
+}
+
+// WithTel sets Options.Telemetry of middleware options T
+func WithTel[T any, P embedder[T]](t *tel.Telemetry) func(*T) {
+	return func(opts *T) {
+		P(opts).shared().Telemetry = t
+	}
+}
+
+// WithMeterProvider sets Options.MeterProvider of middleware options T
+func WithMeterProvider[T any, P embedder[T]](mp metric.MeterProvider) func(*T) {
+	return func(opts *T) {
+		P(opts).shared().MeterProvider = mp
+	}
+}
@@ -0,0 +1,34 @@
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tel-io/tel/v2"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+)
+
+type middlewareOptions struct {
+	Options
+
+	Log bool
+}
+
+type middlewareOption func(*middlewareOptions)
+
+func TestOptions(t *testing.T) {
+	tele := tel.NewNull()
+	mp := metricnoop.NewMeterProvider()
+
+	opts := Apply(middlewareOptions{Log: true},
+		middlewareOption(WithTel[middlewareOptions](&tele)),
+		middlewareOption(WithMeterProvider[middlewareOptions](mp)),
+	)
+
+	assert.True(t, opts.Log)
+	assert.Same(t, &tele, opts.Telemetry)
+	assert.Equal(t, mp, opts.Meters())
+
+	var empty Options
+	assert.Equal(t, tel.Global().MetricProvider(), empty.Meters())
+}
@@ -0,0 +1,45 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+)
+
+const CardinalityEndpoint = "/debug/cardinality"
+
+// CardinalityResponse is body of GET /debug/cardinality
+type CardinalityResponse struct {
+	Meters  []cardinalitydetector.ScopeReport `json:"meters"`
+	Tracers []cardinalitydetector.ScopeReport `json:"tracers"`
+}
+
+type cardinalityHandler struct {
+	meters, tracers cardinalitydetector.Reporter
+}
+
+func (h *cardinalityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	res := CardinalityResponse{
+		Meters:  report(h.meters),
+		Tracers: report(h.tracers),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func report(r cardinalitydetector.Reporter) []cardinalitydetector.ScopeReport {
+	if r == nil {
+		return []cardinalitydetector.ScopeReport{}
+	}
+
+	return r.CardinalityReport()
+}
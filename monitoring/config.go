@@ -1,7 +1,11 @@
 package monitoring
 
 import (
+	"net/http"
+
 	health "github.com/tel-io/tel/v2/monitoring/heallth"
+	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+	"github.com/tel-io/tel/v2/pkg/loglevel"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
 )
@@ -12,6 +16,13 @@ type config struct {
 
 	checker []health.Checker
 
+	levels      *loglevel.Levels
+	levelsWrite bool
+
+	metrics http.Handler
+
+	meters, tracers cardinalitydetector.Reporter
+
 	provider metric.MeterProvider
 }
 
@@ -47,6 +58,35 @@ func WithChecker(ch ...health.Checker) Option {
 	})
 }
 
+// WithLogLevels exposes log levels via GET /log/level, see WithLogLevelWrite for PUT
+func WithLogLevels(levels *loglevel.Levels) Option {
+	return optionFunc(func(c *config) {
+		c.levels = levels
+	})
+}
+
+// WithLogLevelWrite allows to change log levels via PUT /log/level, it's allowed in debug mode as well
+func WithLogLevelWrite(enable bool) Option {
+	return optionFunc(func(c *config) {
+		c.levelsWrite = enable
+	})
+}
+
+// WithMetricsHandler serves prometheus pull endpoint on /metrics, nil handler disables it
+func WithMetricsHandler(h http.Handler) Option {
+	return optionFunc(func(c *config) {
+		c.metrics = h
+	})
+}
+
+// WithCardinality serves cardinality reports of meters and tracers on /debug/cardinality, nil reporter is skipped
+func WithCardinality(meters, tracers cardinalitydetector.Reporter) Option {
+	return optionFunc(func(c *config) {
+		c.meters = meters
+		c.tracers = tracers
+	})
+}
+
 func WithMetricProvider(provider metric.MeterProvider) Option {
 	return optionFunc(func(c *config) {
 		c.provider = provider
@@ -0,0 +1,77 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tel-io/tel/v2/pkg/loglevel"
+	"go.uber.org/zap/zapcore"
+)
+
+const LogLevelEndpoint = "/log/level"
+
+// LogLevelRequest is body of PUT /log/level, empty output changes all outputs,
+// ttl is optional duration like 10m after which level reverts
+type LogLevelRequest struct {
+	Output string `json:"output,omitempty"`
+	Level  string `json:"level"`
+	TTL    string `json:"ttl,omitempty"`
+}
+
+type logLevelHandler struct {
+	levels *loglevel.Levels
+	// write allows PUT, monitor port is often reachable by anyone in cluster
+	write bool
+}
+
+func (h *logLevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet:
+	case r.Method == http.MethodPut && h.write:
+		if err := h.set(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", h.allow())
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.levels.States())
+}
+
+func (h *logLevelHandler) allow() string {
+	if h.write {
+		return "GET, PUT"
+	}
+
+	return "GET"
+}
+
+func (h *logLevelHandler) set(r *http.Request) error {
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.WithMessage(err, "decode request")
+	}
+
+	var lvl zapcore.Level
+	if err := lvl.Set(req.Level); err != nil {
+		return errors.WithMessagef(err, "level %q", req.Level)
+	}
+
+	var ttl time.Duration
+
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			return errors.WithMessagef(err, "ttl %q", req.TTL)
+		}
+	}
+
+	return h.levels.Set(req.Output, lvl, ttl)
+}
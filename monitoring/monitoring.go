@@ -13,6 +13,7 @@ import (
 
 const (
 	HealthEndpoint      = "/health"
+	MetricsEndpoint     = "/metrics"
 	PprofIndexEndpoint  = "/debug/pprof"
 	EchoShutdownTimeout = 5 * time.Second
 )
@@ -51,6 +52,21 @@ func (m *Monitor) route() {
 	mux := http.NewServeMux()
 	mux.Handle(HealthEndpoint, m.health)
 
+	if m.config.levels != nil {
+		mux.Handle(LogLevelEndpoint, &logLevelHandler{
+			levels: m.config.levels,
+			write:  m.config.levelsWrite || m.config.debug,
+		})
+	}
+
+	if m.config.metrics != nil {
+		mux.Handle(MetricsEndpoint, m.config.metrics)
+	}
+
+	if m.config.meters != nil || m.config.tracers != nil {
+		mux.Handle(CardinalityEndpoint, &cardinalityHandler{meters: m.config.meters, tracers: m.config.tracers})
+	}
+
 	if m.config.debug {
 		mux.Handle(PprofIndexEndpoint+"/", http.HandlerFunc(pprof.Index))
 		mux.Handle(PprofIndexEndpoint+"/cmdline/", http.HandlerFunc(pprof.Cmdline))
@@ -2,11 +2,18 @@ package monitoring
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+	"github.com/tel-io/tel/v2/pkg/loglevel"
+	"go.uber.org/zap/zapcore"
 )
 
 // Test_monitor_Start check if health endpoint is working
@@ -26,3 +33,137 @@ func Test_monitor_Start(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func Test_monitor_LogLevel(t *testing.T) {
+	levels := loglevel.New()
+	stdout := levels.Add(loglevel.OutputStdout, zapcore.InfoLevel)
+	otlp := levels.Add(loglevel.OutputOTLP, zapcore.InfoLevel)
+
+	m := NewMon(WithLogLevels(levels), WithLogLevelWrite(true))
+	m.route()
+
+	s := httptest.NewServer(m.server.Handler)
+	defer s.Close()
+
+	put := func(body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPut, s.URL+LogLevelEndpoint, strings.NewReader(body))
+		require.NoError(t, err)
+
+		res, err := s.Client().Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		return res
+	}
+
+	res := put(`{"output":"otlp","level":"debug","ttl":"1h"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, zapcore.DebugLevel, otlp.Level())
+	assert.Equal(t, zapcore.InfoLevel, stdout.Level())
+
+	assert.Equal(t, http.StatusBadRequest, put(`{"level":"loud"}`).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, put(`{"output":"file","level":"debug"}`).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, put(`{"level":"debug","ttl":"soon"}`).StatusCode)
+
+	res, err := s.Client().Get(s.URL + LogLevelEndpoint)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var states []loglevel.State
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&states))
+	require.Len(t, states, 2)
+	assert.Equal(t, loglevel.OutputOTLP, states[0].Output)
+	assert.Equal(t, zapcore.DebugLevel, states[0].Level)
+	assert.NotNil(t, states[0].Until)
+}
+
+func Test_monitor_LogLevelReadOnly(t *testing.T) {
+	levels := loglevel.New()
+	stdout := levels.Add(loglevel.OutputStdout, zapcore.InfoLevel)
+
+	m := NewMon(WithLogLevels(levels))
+	m.route()
+
+	s := httptest.NewServer(m.server.Handler)
+	defer s.Close()
+
+	req, err := http.NewRequest(http.MethodPut, s.URL+LogLevelEndpoint, strings.NewReader(`{"level":"debug"}`))
+	require.NoError(t, err)
+
+	res, err := s.Client().Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	assert.Equal(t, "GET", res.Header.Get("Allow"))
+	assert.Equal(t, zapcore.InfoLevel, stdout.Level())
+
+	res, err = s.Client().Get(s.URL + LogLevelEndpoint)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func Test_monitor_Metrics(t *testing.T) {
+	m := NewMon()
+	m.route()
+
+	s := httptest.NewServer(m.server.Handler)
+	defer s.Close()
+
+	r, err := s.Client().Get(s.URL + MetricsEndpoint)
+	require.NoError(t, err)
+	_ = r.Body.Close()
+	assert.Equal(t, http.StatusNotFound, r.StatusCode, "disabled without handler")
+
+	m = NewMon(WithMetricsHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("up 1\n"))
+	})))
+	m.route()
+
+	s2 := httptest.NewServer(m.server.Handler)
+	defer s2.Close()
+
+	r, err = s2.Client().Get(s2.URL + MetricsEndpoint)
+	require.NoError(t, err)
+
+	b, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	_ = r.Body.Close()
+	assert.Equal(t, "up 1\n", string(b))
+}
+
+type reporterFunc func() []cardinalitydetector.ScopeReport
+
+func (f reporterFunc) CardinalityReport() []cardinalitydetector.ScopeReport {
+	return f()
+}
+
+func Test_monitor_Cardinality(t *testing.T) {
+	meters := reporterFunc(func() []cardinalitydetector.ScopeReport {
+		return []cardinalitydetector.ScopeReport{{
+			Scope: "app",
+			Count: 1,
+			Limit: 10,
+			Instruments: []cardinalitydetector.InstrumentReport{{
+				Name:       "requests",
+				Attributes: []cardinalitydetector.AttributeReport{{Key: "user_id", Count: 5, Limit: 5, Blocked: true}},
+			}},
+		}}
+	})
+
+	m := NewMon(WithCardinality(meters, nil))
+	m.route()
+
+	s := httptest.NewServer(m.server.Handler)
+	defer s.Close()
+
+	res, err := s.Client().Get(s.URL + CardinalityEndpoint)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var body CardinalityResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, meters(), body.Meters)
+	assert.Empty(t, body.Tracers)
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/tel-io/tel/v2/otlplog/otlpconfig"
 	"github.com/tel-io/tel/v2/otlplog/retry"
+	"github.com/tel-io/tel/v2/pkg/selfmetric"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -48,6 +49,8 @@ type Connection struct {
 	requestFunc          retry.RequestFunc
 	metadata             metadata.MD
 	newConnectionHandler func(cc *grpc.ClientConn)
+	connectedHandler     func()
+	metrics              *selfmetric.Metrics
 
 	// these channels are created once
 	disconnectedCh             chan bool
@@ -63,6 +66,7 @@ type Connection struct {
 func NewConnection(cfg otlpconfig.Config, sCfg otlpconfig.SignalConfig, handler func(cc *grpc.ClientConn)) *Connection {
 	c := new(Connection)
 	c.newConnectionHandler = handler
+	c.metrics = selfmetric.New(cfg.MeterProvider, selfmetric.SignalLogs)
 	c.cfg = cfg
 	c.requestFunc = cfg.RetryConfig.RequestFunc(evaluate)
 	c.SCfg = sCfg
@@ -75,6 +79,12 @@ func NewConnection(cfg otlpconfig.Config, sCfg otlpconfig.SignalConfig, handler
 	return c
 }
 
+// OnConnected sets handler called each time connection is (re)established.
+// Should be called before StartConnection.
+func (c *Connection) OnConnected(handler func()) {
+	c.connectedHandler = handler
+}
+
 func (c *Connection) StartConnection(ctx context.Context) error {
 	c.stopCh = make(chan struct{})
 	c.disconnectedCh = make(chan bool, 1)
@@ -119,6 +129,9 @@ func (c *Connection) SetStateDisconnected(err error) {
 
 func (c *Connection) setStateConnected() {
 	c.saveLastConnectError(nil)
+	if c.connectedHandler != nil {
+		c.connectedHandler()
+	}
 }
 
 func (c *Connection) Connected() bool {
@@ -168,6 +181,7 @@ func (c *Connection) indefiniteBackgroundConnection() {
 		}
 
 		if err := c.connect(context.Background()); err == nil {
+			c.metrics.Reconnected(context.Background())
 			c.setStateConnected()
 		} else {
 			// this code is unreachable in most cases
@@ -310,9 +324,14 @@ func (c *Connection) DoRequest(ctx context.Context, fn func(context.Context) err
 func evaluate(err error) (bool, time.Duration) {
 	s := status.Convert(err)
 	switch s.Code() {
+	case codes.ResourceExhausted:
+		// Retry only if the server signals that a recovery from resource
+		// exhaustion is possible, otherwise message is too large and
+		// retrying the same payload is pointless.
+		throttle := throttleDelay(s)
+		return throttle > 0, throttle
 	case codes.Canceled,
 		codes.DeadlineExceeded,
-		codes.ResourceExhausted,
 		codes.Aborted,
 		codes.OutOfRange,
 		codes.Unavailable,
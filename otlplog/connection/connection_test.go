@@ -107,7 +107,7 @@ func TestEvaluate(t *testing.T) {
 		codes.NotFound:           false,
 		codes.AlreadyExists:      false,
 		codes.PermissionDenied:   false,
-		codes.ResourceExhausted:  true,
+		codes.ResourceExhausted:  false,
 		codes.FailedPrecondition: false,
 		codes.Aborted:            true,
 		codes.OutOfRange:         true,
@@ -122,6 +122,16 @@ func TestEvaluate(t *testing.T) {
 		got, _ := evaluate(status.Error(c, ""))
 		assert.Equalf(t, want, got, "evaluate(%s)", c)
 	}
+
+	// ResourceExhausted is retryable only when server sends RetryInfo
+	s, err := status.New(codes.ResourceExhausted, "throttled").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)},
+	)
+	require.NoError(t, err)
+
+	got, throttle := evaluate(s.Err())
+	assert.True(t, got)
+	assert.Equal(t, time.Second, throttle)
 }
 
 func TestDoRequest(t *testing.T) {
@@ -7,7 +7,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/tel-io/tel/v2/pkg/selfmetric"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -22,6 +24,9 @@ type batchProcessor struct {
 	queue   chan Log
 	dropped uint32
 
+	metrics      *selfmetric.Metrics
+	metricsQueue metric.Registration
+
 	batch      []Log
 	batchMutex sync.Mutex
 	timer      *time.Timer
@@ -35,6 +40,16 @@ type batchProcessor struct {
 //
 // If the exporter is nil, the span processor will preform no action.
 func NewBatchLogProcessor(exporter Exporter, options ...trace.BatchSpanProcessorOption) LogProcessor {
+	return NewBatchLogProcessorWithMeterProvider(exporter, nil, options...)
+}
+
+// NewBatchLogProcessorWithMeterProvider is NewBatchLogProcessor reporting queue size and dropped logs to mp,
+// global provider is used if mp is nil
+func NewBatchLogProcessorWithMeterProvider(
+	exporter Exporter,
+	mp metric.MeterProvider,
+	options ...trace.BatchSpanProcessorOption,
+) LogProcessor {
 	o := trace.BatchSpanProcessorOptions{
 		BatchTimeout:       trace.DefaultScheduleDelay * time.Millisecond,
 		ExportTimeout:      trace.DefaultExportTimeout * time.Millisecond,
@@ -51,6 +66,14 @@ func NewBatchLogProcessor(exporter Exporter, options ...trace.BatchSpanProcessor
 		timer:  time.NewTimer(o.BatchTimeout),
 		queue:  make(chan Log, o.MaxQueueSize),
 		stopCh: make(chan struct{}),
+
+		metrics: selfmetric.New(mp, selfmetric.SignalLogs),
+	}
+
+	var err error
+	bsp.metricsQueue, err = bsp.metrics.ObserveQueue(func() int { return len(bsp.queue) })
+	if err != nil {
+		otel.Handle(err)
 	}
 
 	bsp.stopWait.Add(1)
@@ -87,6 +110,11 @@ func (bsp *batchProcessor) Shutdown(ctx context.Context) error {
 					otel.Handle(err)
 				}
 			}
+			if bsp.metricsQueue != nil {
+				if err := bsp.metricsQueue.Unregister(); err != nil {
+					otel.Handle(err)
+				}
+			}
 			close(wait)
 		}()
 		// Wait until the wait group is done or the context is canceled
@@ -158,6 +186,7 @@ func (bsp *batchProcessor) exportLogs(ctx context.Context) error {
 		bsp.batch = bsp.batch[:0]
 
 		if err != nil {
+			bsp.metrics.Dropped(ctx, l)
 			return err
 		}
 	}
@@ -274,6 +303,7 @@ func (bsp *batchProcessor) enqueueBlockOnQueueFull(ctx context.Context, sd Log,
 		return true
 	default:
 		atomic.AddUint32(&bsp.dropped, 1)
+		bsp.metrics.Dropped(ctx, 1)
 	}
 	return false
 }
@@ -0,0 +1,44 @@
+package logskd
+
+import (
+	"context"
+
+	"go.uber.org/multierr"
+)
+
+type multiProcessor []LogProcessor
+
+// NewMultiLogProcessor writes each log to all processors, single processor is returned as is
+func NewMultiLogProcessor(processors ...LogProcessor) LogProcessor {
+	if len(processors) == 1 {
+		return processors[0]
+	}
+
+	return multiProcessor(processors)
+}
+
+func (mp multiProcessor) Write(s Log) {
+	for _, p := range mp {
+		p.Write(s)
+	}
+}
+
+func (mp multiProcessor) Shutdown(ctx context.Context) error {
+	var err error
+
+	for _, p := range mp {
+		err = multierr.Append(err, p.Shutdown(ctx))
+	}
+
+	return err
+}
+
+func (mp multiProcessor) ForceFlush(ctx context.Context) error {
+	var err error
+
+	for _, p := range mp {
+		err = multierr.Append(err, p.ForceFlush(ctx))
+	}
+
+	return err
+}
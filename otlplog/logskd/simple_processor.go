@@ -0,0 +1,57 @@
+package logskd
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+)
+
+// simpleProcessor is a LogProcessor that synchronously sends each log to an Exporter.
+// It's not recommended for production, use it for tests and debugging.
+type simpleProcessor struct {
+	mu sync.Mutex
+	e  Exporter
+
+	stopOnce sync.Once
+}
+
+// NewSimpleLogProcessor returns a new LogProcessor which exports every log immediately on Write.
+func NewSimpleLogProcessor(exporter Exporter) LogProcessor {
+	return &simpleProcessor{e: exporter}
+}
+
+// Write exports log immediately
+func (sp *simpleProcessor) Write(s Log) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	if sp.e == nil {
+		return
+	}
+
+	if err := sp.e.ExportLogs(context.Background(), []Log{s}); err != nil {
+		otel.Handle(err)
+	}
+}
+
+// Shutdown shuts down exporter, subsequent writes are ignored
+func (sp *simpleProcessor) Shutdown(ctx context.Context) error {
+	var err error
+	sp.stopOnce.Do(func() {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+
+		if sp.e != nil {
+			err = sp.e.Shutdown(ctx)
+			sp.e = nil
+		}
+	})
+
+	return err
+}
+
+// ForceFlush does nothing as there is nothing to flush
+func (sp *simpleProcessor) ForceFlush(context.Context) error {
+	return nil
+}
@@ -89,6 +89,16 @@ func (e *EnvOptionsReader) GetOptionsFromEnv() []GenericOption {
 
 		opts = append(opts, WithEndpoint(trimSchema(v)))
 	}
+	// LOGS_ keys are applied last and take precedence, TRACES_ keys are kept for backward compatibility
+	if v, ok := e.getEnvValue("LOGS_ENDPOINT"); ok {
+		if isInsecureEndpoint(v) {
+			opts = append(opts, WithInsecure())
+		} else {
+			opts = append(opts, WithSecure())
+		}
+
+		opts = append(opts, WithEndpoint(trimSchema(v)))
+	}
 
 	// Certificate File
 	if path, ok := e.getEnvValue("CERTIFICATE"); ok {
@@ -105,6 +115,13 @@ func (e *EnvOptionsReader) GetOptionsFromEnv() []GenericOption {
 			otel.Handle(fmt.Errorf("failed to configure otlp traces exporter certificate '%s': %w", path, err))
 		}
 	}
+	if path, ok := e.getEnvValue("LOGS_CERTIFICATE"); ok {
+		if tls, err := e.readTLSConfig(path); err == nil {
+			opts = append(opts, WithTLSClientConfig(tls))
+		} else {
+			otel.Handle(fmt.Errorf("failed to configure otlp logs exporter certificate '%s': %w", path, err))
+		}
+	}
 
 	// Headers
 	if h, ok := e.getEnvValue("HEADERS"); ok {
@@ -113,6 +130,9 @@ func (e *EnvOptionsReader) GetOptionsFromEnv() []GenericOption {
 	if h, ok := e.getEnvValue("TRACES_HEADERS"); ok {
 		opts = append(opts, WithHeaders(stringToHeader(h)))
 	}
+	if h, ok := e.getEnvValue("LOGS_HEADERS"); ok {
+		opts = append(opts, WithHeaders(stringToHeader(h)))
+	}
 
 	// Compression
 	if c, ok := e.getEnvValue("COMPRESSION"); ok {
@@ -121,6 +141,9 @@ func (e *EnvOptionsReader) GetOptionsFromEnv() []GenericOption {
 	if c, ok := e.getEnvValue("TRACES_COMPRESSION"); ok {
 		opts = append(opts, WithCompression(stringToCompression(c)))
 	}
+	if c, ok := e.getEnvValue("LOGS_COMPRESSION"); ok {
+		opts = append(opts, WithCompression(stringToCompression(c)))
+	}
 	// Timeout
 	if t, ok := e.getEnvValue("TIMEOUT"); ok {
 		if d, err := strconv.Atoi(t); err == nil {
@@ -132,6 +155,11 @@ func (e *EnvOptionsReader) GetOptionsFromEnv() []GenericOption {
 			opts = append(opts, WithTimeout(time.Duration(d)*time.Millisecond))
 		}
 	}
+	if t, ok := e.getEnvValue("LOGS_TIMEOUT"); ok {
+		if d, err := strconv.Atoi(t); err == nil {
+			opts = append(opts, WithTimeout(time.Duration(d)*time.Millisecond))
+		}
+	}
 
 	return opts
 }
@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/tel-io/tel/v2/otlplog/retry"
+	"github.com/tel-io/tel/v2/pkg/wal"
+	"go.opentelemetry.io/otel/metric"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
@@ -28,9 +30,15 @@ const (
 	// DefaultTracesPath is a default URL path for endpoint that
 	// receives spans.
 	DefaultTracesPath string = "/v1/traces"
+	// DefaultLogsPath is a default URL path for endpoint that
+	// receives logs.
+	DefaultLogsPath string = "/v1/logs"
 	// DefaultTimeout is a default max waiting time for the backend to process
 	// each span batch.
 	DefaultTimeout time.Duration = 10 * time.Second
+	// DefaultMaxRequestSize is a default max size of single export request,
+	// it matches default max receive message size of grpc server.
+	DefaultMaxRequestSize int = 4 << 20
 )
 
 type (
@@ -53,11 +61,24 @@ type (
 
 		RetryConfig retry.Config
 
+		// MaxRequestSize limits encoded size of single export request in bytes,
+		// bigger requests are split. Zero disables splitting.
+		MaxRequestSize int
+
+		// HTTP configurations
+		Marshaler Marshaler
+
 		// gRPC configurations
 		ReconnectionPeriod time.Duration
 		ServiceConfig      string
 		DialOptions        []grpc.DialOption
 		GRPCConn           *grpc.ClientConn
+
+		// WAL keeps requests while collector is unreachable
+		WAL *wal.WAL
+
+		// MeterProvider of connection and export self-metrics, global provider is used if nil
+		MeterProvider metric.MeterProvider
 	}
 )
 
@@ -69,7 +90,8 @@ func NewDefaultConfig() Config {
 			Compression: NoCompression,
 			Timeout:     DefaultTimeout,
 		},
-		RetryConfig: retry.DefaultConfig,
+		RetryConfig:    retry.DefaultConfig,
+		MaxRequestSize: DefaultMaxRequestSize,
 	}
 
 	return c
@@ -236,3 +258,9 @@ func WithTimeout(duration time.Duration) GenericOption {
 		cfg.Traces.Timeout = duration
 	})
 }
+
+func WithMaxRequestSize(size int) GenericOption {
+	return newGenericOption(func(cfg *Config) {
+		cfg.MaxRequestSize = size
+	})
+}
@@ -111,6 +111,17 @@ func TestConfigs(t *testing.T) {
 				assert.Equal(t, "env_traces_endpoint", c.Traces.Endpoint)
 			},
 		},
+		{
+			name: "Test Environment Logs Specific Endpoint",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "overrode_by_logs_specific",
+				"OTEL_EXPORTER_OTLP_LOGS_ENDPOINT":   "http://env_logs_endpoint",
+			},
+			asserts: func(t *testing.T, c *otlpconfig.Config, grpcOption bool) {
+				assert.Equal(t, "env_logs_endpoint", c.Traces.Endpoint)
+				assert.Equal(t, true, c.Traces.Insecure)
+			},
+		},
 		{
 			name: "Test Mixed Environment and With Endpoint",
 			opts: []otlpconfig.GenericOption{
@@ -281,6 +292,16 @@ func TestConfigs(t *testing.T) {
 				assert.Equal(t, map[string]string{"h1": "v1", "h2": "v2"}, c.Traces.Headers)
 			},
 		},
+		{
+			name: "Test Environment Logs Specific Headers",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_HEADERS":      "overrode_by_signal_specific",
+				"OTEL_EXPORTER_OTLP_LOGS_HEADERS": "h1=v1",
+			},
+			asserts: func(t *testing.T, c *otlpconfig.Config, grpcOption bool) {
+				assert.Equal(t, map[string]string{"h1": "v1"}, c.Traces.Headers)
+			},
+		},
 		{
 			name: "Test Mixed Environment and With Headers",
 			env:  map[string]string{"OTEL_EXPORTER_OTLP_HEADERS": "h1=v1,h2=v2"},
@@ -18,6 +18,9 @@ const (
 	// DefaultCollectorPort is the port the Exporter will attempt connect to
 	// if no collector port is provided.
 	DefaultCollectorPort uint16 = 4317
+	// DefaultCollectorHTTPPort is the port the HTTP Exporter will attempt
+	// connect to if no collector port is provided.
+	DefaultCollectorHTTPPort uint16 = 4318
 	// DefaultCollectorHost is the host address the Exporter will attempt
 	// connect to if no collector address is provided.
 	DefaultCollectorHost string = "localhost"
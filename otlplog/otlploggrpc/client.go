@@ -19,11 +19,18 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/tel-io/tel/v2/otlplog"
 	"github.com/tel-io/tel/v2/otlplog/connection"
 	"github.com/tel-io/tel/v2/otlplog/otlpconfig"
+	"github.com/tel-io/tel/v2/pkg/logtransform"
+	"github.com/tel-io/tel/v2/pkg/wal"
+	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	coltracepb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	tracepb "go.opentelemetry.io/proto/otlp/logs/v1"
@@ -34,6 +41,18 @@ type client struct {
 
 	lock         sync.Mutex
 	tracesClient coltracepb.LogsServiceClient
+
+	// maxRequestSize is upper bound of single export request in bytes learned from collector rejections,
+	// it grows back to configured requestSizeLimit after growRequestSizeAfter successful requests
+	maxRequestSize   atomic.Int64
+	requestSizeLimit int64
+	exported         atomic.Int64
+
+	// wal keeps logs while client is disconnected, replayCh triggers sending them
+	wal        *wal.WAL
+	replayCh   chan struct{}
+	replayStop chan struct{}
+	replayDone chan struct{}
 }
 
 var _ otlplog.Client = (*client)(nil)
@@ -42,6 +61,9 @@ var (
 	errNoClient = errors.New("no client")
 )
 
+// growRequestSizeAfter is number of successful requests after which learned request size limit is doubled
+const growRequestSizeAfter = 64
+
 // NewClient creates a new gRPC trace client.
 func NewClient(opts ...Option) otlplog.Client {
 	cfg := otlpconfig.NewDefaultConfig()
@@ -50,8 +72,15 @@ func NewClient(opts ...Option) otlplog.Client {
 		opt.applyGRPCOption(&cfg)
 	}
 
-	c := &client{}
+	c := &client{wal: cfg.WAL}
 	c.connection = connection.NewConnection(cfg, cfg.Traces, c.handleNewConnection)
+	c.requestSizeLimit = int64(max(cfg.MaxRequestSize, 0))
+	c.maxRequestSize.Store(c.requestSizeLimit)
+
+	if c.wal != nil {
+		c.replayCh = make(chan struct{}, 1)
+		c.connection.OnConnected(c.triggerReplay)
+	}
 
 	return c
 }
@@ -68,18 +97,134 @@ func (c *client) handleNewConnection(cc *grpc.ClientConn) {
 
 // Start establishes a connection to the collector.
 func (c *client) Start(ctx context.Context) error {
+	if c.wal != nil {
+		c.replayStop = make(chan struct{})
+		c.replayDone = make(chan struct{})
+
+		go c.replayLoop()
+	}
+
 	return c.connection.StartConnection(ctx)
 }
 
 // Stop shuts down the connection to the collector.
 func (c *client) Stop(ctx context.Context) error {
-	return c.connection.Shutdown(ctx)
+	if c.replayStop == nil {
+		return c.connection.Shutdown(ctx)
+	}
+
+	// in-flight replay is interrupted by connection shutdown, its entry is kept in wal
+	close(c.replayStop)
+	err := c.connection.Shutdown(ctx)
+
+	select {
+	case <-c.replayDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return err
 }
 
 // UploadLogs sends a batch of logs to the collector.
+// Batch is split into requests which not exceed max request size,
+// requests rejected by collector with ResourceExhausted are halved and sent again.
+// With WAL logs are stored on disk while client is disconnected.
 func (c *client) UploadLogs(ctx context.Context, protoSpans *tracepb.ResourceLogs) error {
+	if c.wal == nil {
+		_, err := c.send(ctx, protoSpans)
+		return err
+	}
+
+	// keep order: while something is waiting for replay new logs go to the tail of wal
+	if !c.connection.Connected() || c.wal.Len() > 0 {
+		if err := c.spill(protoSpans); err != nil {
+			return err
+		}
+
+		c.triggerReplay()
+
+		return nil
+	}
+
+	rest, err := c.send(ctx, protoSpans)
+	if len(rest) == 0 {
+		return err
+	}
+
+	if spillErr := c.spill(rest...); spillErr != nil {
+		return errors.Join(err, spillErr)
+	}
+
+	return nil
+}
+
+func (c *client) spill(rls ...*tracepb.ResourceLogs) error {
+	var errs []error
+	for _, rl := range rls {
+		data, err := proto.Marshal(rl)
+		if err == nil {
+			err = c.wal.Write(data)
+		}
+
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *client) triggerReplay() {
+	select {
+	case c.replayCh <- struct{}{}:
+	default:
+	}
+}
+
+// replayLoop sends logs stored in wal each time connection is established
+func (c *client) replayLoop() {
+	defer close(c.replayDone)
+
+	for {
+		select {
+		case <-c.replayStop:
+			return
+		case <-c.replayCh:
+		}
+
+		if !c.connection.Connected() {
+			continue
+		}
+
+		err := c.wal.Replay(func(data []byte) error {
+			rl := &tracepb.ResourceLogs{}
+			if err := proto.Unmarshal(data, rl); err != nil {
+				// broken entry can't be sent, drop it
+				return nil //nolint:nilerr
+			}
+
+			// records rejected by collector are dropped, only parts not delivered because of connection failure are kept
+			rest, err := c.send(context.Background(), rl)
+			if len(rest) == 0 {
+				return nil
+			}
+
+			data, marshalErr := proto.Marshal(logtransform.Merge(rest...))
+			if marshalErr != nil {
+				return err
+			}
+
+			return &wal.PartialError{Rest: data, Err: err}
+		})
+		if err != nil {
+			otel.Handle(err)
+		}
+	}
+}
+
+// send exports chunks of protoSpans, on connection failure it returns parts which were not delivered
+func (c *client) send(ctx context.Context, protoSpans *tracepb.ResourceLogs) ([]*tracepb.ResourceLogs, error) {
 	if !c.connection.Connected() {
-		return fmt.Errorf("log exporter is disconnected from the server %s: %w",
+		return []*tracepb.ResourceLogs{protoSpans}, fmt.Errorf("log exporter is disconnected from the server %s: %w",
 			c.connection.SCfg.Endpoint, c.connection.LastConnectError())
 	}
 
@@ -89,24 +234,135 @@ func (c *client) UploadLogs(ctx context.Context, protoSpans *tracepb.ResourceLog
 	defer tCancel()
 
 	ctx = c.connection.ContextWithMetadata(ctx)
-	err := func() error {
-		c.lock.Lock()
-		defer c.lock.Unlock()
-		if c.tracesClient == nil {
-			return errNoClient
+
+	var errs []error
+	chunks := logtransform.Split(protoSpans, int(c.maxRequestSize.Load()))
+	for i, chunk := range chunks {
+		rest, err := c.upload(ctx, chunk)
+		if err == nil {
+			continue
 		}
 
-		return c.connection.DoRequest(ctx, func(ctx context.Context) error {
-			_, err := c.tracesClient.Export(ctx, &coltracepb.ExportLogsServiceRequest{
-				ResourceLogs: []*tracepb.ResourceLogs{protoSpans},
-			})
-			return err
-		})
-	}()
+		// only records which can't be split anymore are left in ResourceExhausted state
+		if cause := failure(err); cause != nil {
+			c.connection.SetStateDisconnected(cause)
+			return append(rest, chunks[i+1:]...), errors.Join(append(errs, err)...)
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// upload sends rl and adaptively halves it when collector reports that message is too large.
+// Parts which were not delivered because of other errors are returned in order, rejected records are dropped
+func (c *client) upload(ctx context.Context, rl *tracepb.ResourceLogs) ([]*tracepb.ResourceLogs, error) {
+	err := c.export(ctx, rl)
+	if err == nil {
+		c.growMaxRequestSize()
+		return nil, nil
+	}
+
+	if status.Code(err) != codes.ResourceExhausted {
+		return []*tracepb.ResourceLogs{rl}, err
+	}
+
+	left, right, ok := logtransform.Halve(rl)
+	if !ok {
+		return nil, fmt.Errorf("log record rejected by collector: %w", err)
+	}
 
-	if err != nil {
-		c.connection.SetStateDisconnected(err)
+	c.shrinkMaxRequestSize(max(proto.Size(rl)/2, logtransform.MaxRecordSize(rl)))
+
+	rest, err := c.upload(ctx, left)
+	if len(rest) > 0 {
+		return append(rest, right), err
+	}
+
+	rest, rightErr := c.upload(ctx, right)
+
+	return rest, errors.Join(err, rightErr)
+}
+
+// failure returns first cause of err which isn't ResourceExhausted rejection,
+// joined errors are walked in order as status.Code only sees the first one
+func failure(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if cause := failure(e); cause != nil {
+				return cause
+			}
+		}
+
+		return nil
+	}
+
+	if status.Code(err) == codes.ResourceExhausted {
+		return nil
 	}
 
 	return err
 }
+
+func (c *client) export(ctx context.Context, rl *tracepb.ResourceLogs) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.tracesClient == nil {
+		return errNoClient
+	}
+
+	return c.connection.DoRequest(ctx, func(ctx context.Context) error {
+		_, err := c.tracesClient.Export(ctx, &coltracepb.ExportLogsServiceRequest{
+			ResourceLogs: []*tracepb.ResourceLogs{rl},
+		})
+		return err
+	})
+}
+
+// shrinkMaxRequestSize lowers request size limit learned from rejections of multi-record requests,
+// size is never below the largest record. Zero limit disables splitting and stays as is
+func (c *client) shrinkMaxRequestSize(size int) {
+	if c.requestSizeLimit == 0 {
+		return
+	}
+
+	c.exported.Store(0)
+
+	for {
+		current := c.maxRequestSize.Load()
+		if current <= int64(size) {
+			return
+		}
+
+		if c.maxRequestSize.CompareAndSwap(current, int64(size)) {
+			return
+		}
+	}
+}
+
+// growMaxRequestSize doubles learned request size limit after growRequestSizeAfter successful requests,
+// so limit recovers when collector accepts bigger messages again
+func (c *client) growMaxRequestSize() {
+	if c.maxRequestSize.Load() >= c.requestSizeLimit || c.exported.Add(1) < growRequestSizeAfter {
+		return
+	}
+
+	c.exported.Store(0)
+
+	for {
+		current := c.maxRequestSize.Load()
+		if current >= c.requestSizeLimit {
+			return
+		}
+
+		if c.maxRequestSize.CompareAndSwap(current, min(2*current, c.requestSizeLimit)) {
+			return
+		}
+	}
+}
@@ -20,10 +20,12 @@ import (
 
 	"github.com/tel-io/tel/v2/otlplog/otlpconfig"
 	"github.com/tel-io/tel/v2/otlplog/retry"
+	"github.com/tel-io/tel/v2/pkg/wal"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // Option applies an option to the gRPC driver.
@@ -138,3 +140,26 @@ func WithTimeout(duration time.Duration) Option {
 func WithRetry(settings RetryConfig) Option {
 	return wrappedOption{otlpconfig.WithRetry(retry.Config(settings))}
 }
+
+// WithMaxRequestSize limits encoded size of single export request in bytes.
+// Bigger batches are split into several requests. If unset, the default
+// is 4 MiB which matches default max receive message size of grpc server.
+// Zero disables splitting.
+func WithMaxRequestSize(size int) Option {
+	return wrappedOption{otlpconfig.WithMaxRequestSize(size)}
+}
+
+// WithMeterProvider sets provider of connection self-metrics, global provider is used by default
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return wrappedOption{otlpconfig.NewGRPCOption(func(cfg *otlpconfig.Config) {
+		cfg.MeterProvider = mp
+	})}
+}
+
+// WithWAL keeps logs in w while the client is disconnected from the collector.
+// Stored logs are sent in order once connection is established again.
+func WithWAL(w *wal.WAL) Option {
+	return wrappedOption{otlpconfig.NewGRPCOption(func(cfg *otlpconfig.Config) {
+		cfg.WAL = w
+	})}
+}
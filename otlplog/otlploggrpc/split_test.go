@@ -0,0 +1,294 @@
+package otlploggrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2/otlplog/otlploggrpc"
+	"github.com/tel-io/tel/v2/pkg/wal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+// limitedCollector rejects requests bigger than limit as grpc server does for too large messages
+type limitedCollector struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	limit int
+	// failAt makes collector unavailable for request which comes after failAt accepted ones
+	failAt int
+	// unavailable fails all requests which fit limit
+	unavailable bool
+
+	mu       sync.Mutex
+	rejected int
+	accepted int
+	records  int
+}
+
+func (c *limitedCollector) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if proto.Size(req) > c.limit {
+		c.rejected++
+		return nil, status.Error(codes.ResourceExhausted, "grpc: received message larger than max")
+	}
+
+	if c.unavailable || c.failAt > 0 && c.accepted == c.failAt {
+		c.failAt = 0
+		return nil, status.Error(codes.Unavailable, "collector is restarting")
+	}
+
+	c.accepted++
+
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			c.records += len(sl.LogRecords)
+		}
+	}
+
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func runLimitedCollector(t *testing.T, limit int) (*limitedCollector, string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	c := &limitedCollector{limit: limit}
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, c)
+
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	return c, ln.Addr().String()
+}
+
+func bigResourceLogs(records, bodySize int) *logspb.ResourceLogs {
+	sl := &logspb.ScopeLogs{}
+	for i := 0; i < records; i++ {
+		sl.LogRecords = append(sl.LogRecords, &logspb.LogRecord{
+			Body: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{
+				StringValue: strings.Repeat("x", bodySize),
+			}},
+		})
+	}
+
+	return &logspb.ResourceLogs{ScopeLogs: []*logspb.ScopeLogs{sl}}
+}
+
+func TestClient_UploadLogsSplit(t *testing.T) {
+	ctx := context.Background()
+	mc, addr := runLimitedCollector(t, 16<<10)
+
+	client := otlploggrpc.NewClient(
+		otlploggrpc.WithInsecure(),
+		otlploggrpc.WithEndpoint(addr),
+		otlploggrpc.WithDialOption(grpc.WithBlock()),
+		otlploggrpc.WithMaxRequestSize(8<<10),
+	)
+	require.NoError(t, client.Start(ctx))
+	defer func() { _ = client.Stop(ctx) }()
+
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(100, 1024)))
+	assert.Equal(t, 100, mc.records)
+	assert.Zero(t, mc.rejected)
+}
+
+func TestClient_UploadLogsResourceExhausted(t *testing.T) {
+	ctx := context.Background()
+	mc, addr := runLimitedCollector(t, 16<<10)
+
+	client := otlploggrpc.NewClient(
+		otlploggrpc.WithInsecure(),
+		otlploggrpc.WithEndpoint(addr),
+		otlploggrpc.WithDialOption(grpc.WithBlock()),
+		otlploggrpc.WithMaxRequestSize(1<<20),
+	)
+	require.NoError(t, client.Start(ctx))
+	defer func() { _ = client.Stop(ctx) }()
+
+	// single record which is too large doesn't lower the limit
+	err := client.UploadLogs(ctx, bigResourceLogs(1, 32<<10))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	mc.rejected = 0
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(100, 1024)))
+	assert.Equal(t, 100, mc.records)
+	assert.NotZero(t, mc.rejected)
+
+	// limit learned from rejections is used for next requests
+	rejected := mc.rejected
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(100, 1024)))
+	assert.Equal(t, 200, mc.records)
+	assert.Equal(t, rejected, mc.rejected)
+
+	// limit grows back when collector accepts bigger messages
+	mc.limit = 1 << 20
+	for i := 0; i < 4*64; i++ {
+		require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(1, 10)))
+	}
+
+	accepted := mc.accepted
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(100, 1024)))
+	assert.Equal(t, 1, mc.accepted-accepted)
+}
+
+func TestClient_UploadLogsSplitDisabled(t *testing.T) {
+	ctx := context.Background()
+	mc, addr := runLimitedCollector(t, 16<<10)
+
+	client := otlploggrpc.NewClient(
+		otlploggrpc.WithInsecure(),
+		otlploggrpc.WithEndpoint(addr),
+		otlploggrpc.WithDialOption(grpc.WithBlock()),
+		otlploggrpc.WithMaxRequestSize(0),
+	)
+	require.NoError(t, client.Start(ctx))
+	defer func() { _ = client.Stop(ctx) }()
+
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(100, 1024)))
+	assert.Equal(t, 100, mc.records)
+	assert.NotZero(t, mc.rejected)
+
+	// rejected requests are halved, but zero limit isn't replaced by learned one
+	rejected := mc.rejected
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(100, 1024)))
+	assert.Equal(t, 200, mc.records)
+	assert.Equal(t, 2*rejected, mc.rejected)
+}
+
+func TestClient_UploadLogsWAL(t *testing.T) {
+	ctx := context.Background()
+
+	// reserve address of collector which is started later
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	buf, err := wal.Open(t.TempDir())
+	require.NoError(t, err)
+
+	client := otlploggrpc.NewClient(
+		otlploggrpc.WithInsecure(),
+		otlploggrpc.WithEndpoint(addr),
+		otlploggrpc.WithReconnectionPeriod(10*time.Millisecond),
+		otlploggrpc.WithRetry(otlploggrpc.RetryConfig{}),
+		otlploggrpc.WithTimeout(100*time.Millisecond),
+		otlploggrpc.WithWAL(buf),
+	)
+	require.NoError(t, client.Start(ctx))
+	defer func() { _ = client.Stop(ctx) }()
+
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(10, 10)))
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(10, 10)))
+	assert.Equal(t, 2, buf.Len())
+
+	ln, err = net.Listen("tcp", addr)
+	require.NoError(t, err)
+
+	mc := &limitedCollector{limit: 16 << 10}
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, mc)
+
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	assert.Eventually(t, func() bool {
+		mc.mu.Lock()
+		defer mc.mu.Unlock()
+
+		return mc.records == 20 && buf.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestClient_UploadLogsPartialWAL(t *testing.T) {
+	ctx := context.Background()
+	mc, addr := runLimitedCollector(t, 16<<10)
+	// request is halved to fit, the second half fails with Unavailable after the first one is delivered
+	mc.failAt = 1
+
+	buf, err := wal.Open(t.TempDir())
+	require.NoError(t, err)
+
+	client := otlploggrpc.NewClient(
+		otlploggrpc.WithInsecure(),
+		otlploggrpc.WithEndpoint(addr),
+		otlploggrpc.WithDialOption(grpc.WithBlock()),
+		otlploggrpc.WithReconnectionPeriod(10*time.Millisecond),
+		otlploggrpc.WithRetry(otlploggrpc.RetryConfig{}),
+		otlploggrpc.WithMaxRequestSize(1<<20),
+		otlploggrpc.WithWAL(buf),
+	)
+	require.NoError(t, client.Start(ctx))
+	defer func() { _ = client.Stop(ctx) }()
+
+	require.NoError(t, client.UploadLogs(ctx, bigResourceLogs(100, 1024)))
+
+	// delivered records are not spilled, so nothing is sent twice
+	assert.Eventually(t, func() bool {
+		mc.mu.Lock()
+		defer mc.mu.Unlock()
+
+		return mc.records == 100 && buf.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	assert.Equal(t, 100, mc.records)
+}
+
+func TestClient_UploadLogsRejectedAndUnavailable(t *testing.T) {
+	ctx := context.Background()
+	mc, addr := runLimitedCollector(t, 16<<10)
+	mc.unavailable = true
+
+	buf, err := wal.Open(t.TempDir())
+	require.NoError(t, err)
+
+	client := otlploggrpc.NewClient(
+		otlploggrpc.WithInsecure(),
+		otlploggrpc.WithEndpoint(addr),
+		otlploggrpc.WithDialOption(grpc.WithBlock()),
+		otlploggrpc.WithReconnectionPeriod(10*time.Millisecond),
+		otlploggrpc.WithRetry(otlploggrpc.RetryConfig{}),
+		otlploggrpc.WithMaxRequestSize(1<<20),
+		otlploggrpc.WithWAL(buf),
+	)
+	require.NoError(t, client.Start(ctx))
+	defer func() { _ = client.Stop(ctx) }()
+
+	// the first record is rejected as too large, the second one fails with Unavailable and must be kept
+	rl := bigResourceLogs(2, 10)
+	rl.ScopeLogs[0].LogRecords[0] = bigResourceLogs(1, 32<<10).ScopeLogs[0].LogRecords[0]
+
+	require.NoError(t, client.UploadLogs(ctx, rl))
+	assert.Equal(t, 1, buf.Len())
+
+	mc.mu.Lock()
+	mc.unavailable = false
+	mc.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		mc.mu.Lock()
+		defer mc.mu.Unlock()
+
+		return mc.records == 1 && buf.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
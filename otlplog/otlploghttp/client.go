@@ -0,0 +1,377 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlploghttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tel-io/tel/v2/otlplog"
+	"github.com/tel-io/tel/v2/otlplog/otlpconfig"
+	"github.com/tel-io/tel/v2/otlplog/retry"
+	"github.com/tel-io/tel/v2/pkg/logtransform"
+	"github.com/tel-io/tel/v2/pkg/selfmetric"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+const (
+	contentTypeProto = "application/x-protobuf"
+	contentTypeJSON  = "application/json"
+)
+
+var gzPool = sync.Pool{
+	New: func() interface{} {
+		w := gzip.NewWriter(io.Discard)
+		return w
+	},
+}
+
+// Keep it in sync with golang's DefaultTransport from net/http! We
+// have our own copy to avoid handling a situation where the
+// DefaultTransport is overwritten with some different implementation
+// of http.RoundTripper or it's modified by other package.
+var ourTransport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+type client struct {
+	cfg         otlpconfig.SignalConfig
+	generalCfg  otlpconfig.Config
+	requestFunc retry.RequestFunc
+	client      *http.Client
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+}
+
+var _ otlplog.Client = (*client)(nil)
+
+// NewClient creates a new HTTP log client.
+func NewClient(opts ...Option) otlplog.Client {
+	cfg := otlpconfig.NewDefaultConfig()
+	cfg.Traces.Endpoint = fmt.Sprintf("%s:%d", otlpconfig.DefaultCollectorHost, otlpconfig.DefaultCollectorHTTPPort)
+	cfg.Traces.URLPath = otlpconfig.DefaultLogsPath
+
+	otlpconfig.ApplyHTTPEnvConfigs(&cfg)
+	for _, opt := range opts {
+		opt.applyHTTPOption(&cfg)
+	}
+
+	httpClient := &http.Client{
+		Transport: ourTransport,
+		Timeout:   cfg.Traces.Timeout,
+	}
+
+	if cfg.Traces.TLSCfg != nil {
+		transport := ourTransport.Clone()
+		transport.TLSClientConfig = cfg.Traces.TLSCfg
+		httpClient.Transport = transport
+	}
+
+	httpClient.Transport = selfmetric.New(cfg.MeterProvider, selfmetric.SignalLogs).RoundTripper(httpClient.Transport)
+
+	return &client{
+		cfg:         cfg.Traces,
+		generalCfg:  cfg,
+		requestFunc: cfg.RetryConfig.RequestFunc(evaluate),
+		stopCh:      make(chan struct{}),
+		client:      httpClient,
+	}
+}
+
+// Start does nothing in a HTTP client.
+func (d *client) Start(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	return nil
+}
+
+// Stop shuts down the client and interrupt any in-flight request.
+func (d *client) Stop(ctx context.Context) error {
+	d.stopOnce.Do(func() {
+		close(d.stopCh)
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	return nil
+}
+
+// UploadLogs sends a batch of logs to the collector.
+// Batch is split into requests which not exceed max request size.
+func (d *client) UploadLogs(ctx context.Context, protoLogs *logspb.ResourceLogs) error {
+	for _, chunk := range logtransform.Split(protoLogs, d.generalCfg.MaxRequestSize) {
+		if err := d.upload(ctx, chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *client) upload(ctx context.Context, protoLogs *logspb.ResourceLogs) error {
+	rawRequest, err := d.marshal(&collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{protoLogs},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := d.contextWithStop(ctx)
+	defer cancel()
+
+	ctx = selfmetric.ContextWithSize(ctx, len(rawRequest))
+
+	request, err := d.newRequest(rawRequest)
+	if err != nil {
+		return err
+	}
+
+	return d.requestFunc(ctx, func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		request.reset(ctx)
+		resp, err := d.client.Do(request.Request)
+
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) && urlErr.Temporary() {
+			return newResponseError(http.Header{})
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if resp != nil && resp.Body != nil {
+			defer func() {
+				if err := resp.Body.Close(); err != nil {
+					otel.Handle(err)
+				}
+			}()
+		}
+
+		switch sc := resp.StatusCode; {
+		case sc >= 200 && sc <= 299:
+			// Success, do not retry. Drain the body to reuse the connection.
+			if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+				return err
+			}
+
+			return nil
+		case sc == http.StatusTooManyRequests,
+			sc == http.StatusBadGateway,
+			sc == http.StatusServiceUnavailable,
+			sc == http.StatusGatewayTimeout:
+			// Retry-able failures. Drain the body to reuse the connection.
+			if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+				otel.Handle(err)
+			}
+
+			return newResponseError(resp.Header)
+		default:
+			return fmt.Errorf("failed to send logs to %s: %s", request.URL, resp.Status)
+		}
+	})
+}
+
+func (d *client) marshal(msg proto.Message) ([]byte, error) {
+	if d.generalCfg.Marshaler == otlpconfig.MarshalJSON {
+		return protojson.Marshal(msg)
+	}
+
+	return proto.Marshal(msg)
+}
+
+func (d *client) newRequest(body []byte) (request, error) {
+	u := url.URL{Scheme: d.getScheme(), Host: d.cfg.Endpoint, Path: d.cfg.URLPath}
+
+	r, err := http.NewRequest(http.MethodPost, u.String(), nil)
+	if err != nil {
+		return request{Request: r}, err
+	}
+
+	for k, v := range d.cfg.Headers {
+		r.Header.Set(k, v)
+	}
+
+	if d.generalCfg.Marshaler == otlpconfig.MarshalJSON {
+		r.Header.Set("Content-Type", contentTypeJSON)
+	} else {
+		r.Header.Set("Content-Type", contentTypeProto)
+	}
+
+	req := request{Request: r}
+
+	switch d.cfg.Compression {
+	case otlpconfig.NoCompression:
+		r.ContentLength = (int64)(len(body))
+		req.bodyReader = bodyReader(body)
+	case otlpconfig.GzipCompression:
+		// Ensure the content length is not used.
+		r.ContentLength = -1
+		r.Header.Set("Content-Encoding", "gzip")
+
+		gz := gzPool.Get().(*gzip.Writer) //nolint:forcetypeassert
+		defer gzPool.Put(gz)
+
+		var b bytes.Buffer
+		gz.Reset(&b)
+
+		if _, err := gz.Write(body); err != nil {
+			return req, err
+		}
+
+		// Close needs to be called to ensure body is fully written.
+		if err := gz.Close(); err != nil {
+			return req, err
+		}
+
+		req.bodyReader = bodyReader(b.Bytes())
+	}
+
+	return req, nil
+}
+
+// MarshalLog is the marshaling function used by the logging system to represent this Client.
+func (d *client) MarshalLog() interface{} {
+	return struct {
+		Type     string
+		Endpoint string
+		Insecure bool
+	}{
+		Type:     "otlploghttp",
+		Endpoint: d.cfg.Endpoint,
+		Insecure: d.cfg.Insecure,
+	}
+}
+
+func (d *client) getScheme() string {
+	if d.cfg.Insecure {
+		return "http"
+	}
+
+	return "https"
+}
+
+func (d *client) contextWithStop(ctx context.Context) (context.Context, context.CancelFunc) {
+	// Unify the parent context Done signal with the client's stop
+	// channel.
+	ctx, cancel := context.WithCancel(ctx)
+	go func(ctx context.Context, cancel context.CancelFunc) {
+		select {
+		case <-ctx.Done():
+			// Nothing to do, either canceled or deadline
+			// happened.
+		case <-d.stopCh:
+			cancel()
+		}
+	}(ctx, cancel)
+
+	return ctx, cancel
+}
+
+// bodyReader returns a closure returning a new reader for buf.
+func bodyReader(buf []byte) func() io.ReadCloser {
+	return func() io.ReadCloser {
+		return io.NopCloser(bytes.NewReader(buf))
+	}
+}
+
+// request wraps an http.Request with a resettable body reader.
+type request struct {
+	*http.Request
+
+	// bodyReader allows the same body to be used for multiple requests.
+	bodyReader func() io.ReadCloser
+}
+
+// reset reinitializes the request Body and uses ctx for the request.
+func (r *request) reset(ctx context.Context) {
+	r.Body = r.bodyReader()
+	r.Request = r.Request.WithContext(ctx)
+}
+
+// retryableError represents a request failure that can be retried.
+type retryableError struct {
+	throttle int64
+}
+
+// newResponseError returns a retryableError and will extract any explicit
+// throttle delay contained in headers.
+func newResponseError(header http.Header) error {
+	var rErr retryableError
+	if s, ok := header["Retry-After"]; ok {
+		if t, err := strconv.ParseInt(s[0], 10, 64); err == nil {
+			rErr.throttle = t
+		}
+	}
+
+	return rErr
+}
+
+func (e retryableError) Error() string {
+	return "retry-able request failure"
+}
+
+// evaluate returns if err is retry-able. If it is and it includes an explicit
+// throttling delay, that delay is also returned.
+func evaluate(err error) (bool, time.Duration) {
+	if err == nil {
+		return false, 0
+	}
+
+	// Do not use errors.As here, this should only be flattened one layer.
+	rErr, ok := err.(retryableError) //nolint:errorlint
+	if !ok {
+		return false, 0
+	}
+
+	return true, time.Duration(rErr.throttle) * time.Second
+}
@@ -0,0 +1,157 @@
+package otlploghttp_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2/otlplog/otlploghttp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+type collector struct {
+	*httptest.Server
+
+	requests    atomic.Int32
+	failFirst   int32
+	contentType atomic.Value
+	received    atomic.Value
+}
+
+func runCollector(t *testing.T, failFirst int32) *collector {
+	c := &collector{failFirst: failFirst}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.requests.Add(1) <= c.failFirst {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = gz
+		}
+
+		raw, err := io.ReadAll(body)
+		require.NoError(t, err)
+
+		req := &collogspb.ExportLogsServiceRequest{}
+		if r.Header.Get("Content-Type") == "application/json" {
+			require.NoError(t, protojson.Unmarshal(raw, req))
+		} else {
+			require.NoError(t, proto.Unmarshal(raw, req))
+		}
+
+		c.contentType.Store(r.Header.Get("Content-Type"))
+		c.received.Store(req)
+	}))
+
+	t.Cleanup(c.Close)
+
+	return c
+}
+
+func (c *collector) endpoint() string {
+	return strings.TrimPrefix(c.URL, "http://")
+}
+
+func resourceLogs(body string) *logspb.ResourceLogs {
+	return &logspb.ResourceLogs{
+		ScopeLogs: []*logspb.ScopeLogs{{
+			LogRecords: []*logspb.LogRecord{{SeverityText: body}},
+		}},
+	}
+}
+
+func TestClient_UploadLogs(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []otlploghttp.Option
+		contentType string
+	}{
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+		},
+		{
+			name:        "protobuf with gzip",
+			opts:        []otlploghttp.Option{otlploghttp.WithCompression(otlploghttp.GzipCompression)},
+			contentType: "application/x-protobuf",
+		},
+		{
+			name:        "json with gzip",
+			opts:        []otlploghttp.Option{otlploghttp.WithMarshal(otlploghttp.MarshalJSON), otlploghttp.WithCompression(otlploghttp.GzipCompression)},
+			contentType: "application/json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mc := runCollector(t, 0)
+
+			opts := append([]otlploghttp.Option{
+				otlploghttp.WithInsecure(),
+				otlploghttp.WithEndpoint(mc.endpoint()),
+			}, test.opts...)
+
+			client := otlploghttp.NewClient(opts...)
+			require.NoError(t, client.Start(ctx))
+			require.NoError(t, client.UploadLogs(ctx, resourceLogs(test.name)))
+			require.NoError(t, client.Stop(ctx))
+
+			req := mc.received.Load().(*collogspb.ExportLogsServiceRequest)
+			require.Len(t, req.ResourceLogs, 1)
+			assert.Equal(t, test.name, req.ResourceLogs[0].ScopeLogs[0].LogRecords[0].SeverityText)
+			assert.Equal(t, test.contentType, mc.contentType.Load())
+		})
+	}
+}
+
+func TestClient_UploadLogsRetry(t *testing.T) {
+	ctx := context.Background()
+	mc := runCollector(t, 2)
+
+	client := otlploghttp.NewClient(
+		otlploghttp.WithInsecure(),
+		otlploghttp.WithEndpoint(mc.endpoint()),
+		otlploghttp.WithRetry(otlploghttp.RetryConfig{
+			Enabled:         true,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+			MaxElapsedTime:  time.Second,
+		}),
+	)
+
+	require.NoError(t, client.UploadLogs(ctx, resourceLogs("retry")))
+	assert.Equal(t, int32(3), mc.requests.Load())
+}
+
+func TestClient_UploadLogsNoRetry(t *testing.T) {
+	ctx := context.Background()
+	mc := runCollector(t, 1)
+
+	client := otlploghttp.NewClient(
+		otlploghttp.WithInsecure(),
+		otlploghttp.WithEndpoint(mc.endpoint()),
+		otlploghttp.WithRetry(otlploghttp.RetryConfig{}),
+	)
+
+	assert.Error(t, client.UploadLogs(ctx, resourceLogs("no retry")))
+	assert.Equal(t, int32(1), mc.requests.Load())
+}
@@ -0,0 +1,62 @@
+package pgx
+
+import (
+	"github.com/tel-io/tel/v2"
+	"github.com/tel-io/tel/v2/internal/instrumentation"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// SanitizeFunc converts SQL text into statement put into spans and logs
+type SanitizeFunc func(sql string) string
+
+type Option func(*Options)
+
+func DefaultOptions() Options {
+	return Options{
+		Sanitize: Sanitize,
+		Log:      true,
+	}
+}
+
+func NewOptions(options ...Option) Options {
+	return instrumentation.Apply(DefaultOptions(), options...)
+}
+
+type Options struct {
+	instrumentation.Options
+
+	Sanitize SanitizeFunc
+	// Attributes are added to every span, for instance db.name
+	Attributes []attribute.KeyValue
+	Log        bool
+}
+
+func WithTel(t *tel.Telemetry) Option {
+	return instrumentation.WithTel[Options](t)
+}
+
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return instrumentation.WithMeterProvider[Options](mp)
+}
+
+// WithSanitize replaces default Sanitize, pass nil to keep SQL text as is
+func WithSanitize(fn SanitizeFunc) Option {
+	return func(opts *Options) {
+		opts.Sanitize = fn
+	}
+}
+
+// WithAttributes adds attributes to every span
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(opts *Options) {
+		opts.Attributes = append(opts.Attributes, attrs...)
+	}
+}
+
+// WithLog enables log line of every finished query, failed queries are logged with error level
+func WithLog(b bool) Option {
+	return func(opts *Options) {
+		opts.Log = b
+	}
+}
@@ -0,0 +1,150 @@
+package pgx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Sanitize replaces string, dollar-quoted and numeric literals with ? and collapses whitespace,
+// so statements differing only by inlined values become equal. Placeholders like $1 are kept
+func Sanitize(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	space := false
+	prev := rune(0)
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			i++
+
+			continue
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			// line comment
+			if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+				i += j
+			} else {
+				i = len(sql)
+			}
+
+			space = true
+
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+			prev = ' '
+		}
+
+		space = false
+
+		switch {
+		case c == '\'':
+			i = skipQuoted(sql, i)
+			b.WriteByte('?')
+			prev = '?'
+		case c == '$' && !isIdent(prev) && i+1 < len(sql) && !isDigit(sql[i+1]):
+			if end := skipDollarQuoted(sql, i); end > i {
+				i = end
+				b.WriteByte('?')
+				prev = '?'
+
+				continue
+			}
+
+			b.WriteByte(c)
+			prev = rune(c)
+			i++
+		case c == '$':
+			// positional placeholder
+			j := i + 1
+			for j < len(sql) && isDigit(sql[j]) {
+				j++
+			}
+
+			b.WriteString(sql[i:j])
+			prev = '0'
+			i = j
+		case isDigit(c) && !isIdent(prev):
+			j := i
+			for j < len(sql) && (isDigit(sql[j]) || sql[j] == '.' || sql[j] == 'e' || sql[j] == 'E') {
+				j++
+			}
+
+			b.WriteByte('?')
+			prev = '?'
+			i = j
+		default:
+			b.WriteByte(c)
+			prev = rune(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// operation returns upper-cased first keyword of statement
+func operation(sql string) string {
+	sql = strings.TrimLeftFunc(sql, func(r rune) bool { return unicode.IsSpace(r) || r == '(' })
+
+	end := strings.IndexFunc(sql, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		end = len(sql)
+	}
+
+	return strings.ToUpper(sql[:end])
+}
+
+// skipQuoted returns index after string literal started at i, doubled quote is escaped quote
+func skipQuoted(sql string, i int) int {
+	for j := i + 1; j < len(sql); j++ {
+		if sql[j] != '\'' {
+			continue
+		}
+
+		if j+1 < len(sql) && sql[j+1] == '\'' {
+			j++
+			continue
+		}
+
+		return j + 1
+	}
+
+	return len(sql)
+}
+
+// skipDollarQuoted returns index after $tag$...$tag$ literal started at i, i if there is no literal
+func skipDollarQuoted(sql string, i int) int {
+	end := strings.IndexByte(sql[i+1:], '$')
+	if end < 0 {
+		return i
+	}
+
+	tag := sql[i : i+end+2]
+	for _, r := range tag[1 : len(tag)-1] {
+		if !isIdent(r) {
+			return i
+		}
+	}
+
+	closing := strings.Index(sql[i+len(tag):], tag)
+	if closing < 0 {
+		return len(sql)
+	}
+
+	return i + len(tag) + closing + len(tag)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdent(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,42 @@
+package pgx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  "SELECT * FROM users WHERE id = $1",
+			want: "SELECT * FROM users WHERE id = $1",
+		},
+		{
+			sql:  "SELECT *\n\tFROM users\n WHERE name = 'O''Brien' AND age > 42 LIMIT 10",
+			want: "SELECT * FROM users WHERE name = ? AND age > ? LIMIT ?",
+		},
+		{
+			sql:  "INSERT INTO t1 (a, b) VALUES (1.5e3, $$raw 'text'$$), (-2, $tag$x$tag$)",
+			want: "INSERT INTO t1 (a, b) VALUES (?, ?), (-?, ?)",
+		},
+		{
+			sql:  "SELECT 1 -- comment 2\nFROM dual",
+			want: "SELECT ? FROM dual",
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Sanitize(tt.sql), tt.sql)
+	}
+}
+
+func TestOperation(t *testing.T) {
+	assert.Equal(t, "SELECT", operation("  select 1"))
+	assert.Equal(t, "WITH", operation("with x as (select 1) select * from x"))
+	assert.Equal(t, "SELECT", operation("(SELECT 1) UNION (SELECT 2)"))
+	assert.Equal(t, "", operation(""))
+}
@@ -0,0 +1,243 @@
+// Package pgx provides tracer of github.com/jackc/pgx/v5 which emits spans, query duration metric and logs.
+package pgx
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/tel-io/tel/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const instrumentationName = "github.com/tel-io/tel/v2/pgx"
+
+const (
+	// MetricQueryDuration is duration of queries in seconds by db.operation
+	MetricQueryDuration = "db.client.query.duration"
+
+	// RowsAffectedKey is number of rows affected by statement
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+)
+
+// Operations of calls which are not plain queries
+const (
+	OperationBatch   = "BATCH"
+	OperationCopy    = "COPY"
+	OperationPrepare = "PREPARE"
+	OperationConnect = "CONNECT"
+)
+
+var (
+	_ pgx.QueryTracer    = (*Tracer)(nil)
+	_ pgx.BatchTracer    = (*Tracer)(nil)
+	_ pgx.CopyFromTracer = (*Tracer)(nil)
+	_ pgx.PrepareTracer  = (*Tracer)(nil)
+	_ pgx.ConnectTracer  = (*Tracer)(nil)
+)
+
+// Tracer implements pgx query, batch, copy, prepare and connect tracers, set it to pgx.ConnConfig.Tracer
+type Tracer struct {
+	opts     Options
+	duration metric.Float64Histogram
+}
+
+func NewTracer(opts ...Option) *Tracer {
+	t := &Tracer{opts: NewOptions(opts...)}
+
+	t.createMeasures()
+
+	return t
+}
+
+func (t *Tracer) createMeasures() {
+	var err error
+
+	t.duration, err = t.opts.Meters().Meter(instrumentationName).Float64Histogram(MetricQueryDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of database queries"))
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+type startKey struct{}
+
+// callInfo is stored in context between start and end of call
+type callInfo struct {
+	span      trace.Span
+	start     time.Time
+	operation string
+	statement string
+}
+
+func (t *Tracer) start(ctx context.Context, name, op, statement string, attrs ...attribute.KeyValue) context.Context {
+	tele := t.opts.Tel()
+	if v := tel.ContextValue(ctx); v != nil {
+		tele = v.Copy()
+	}
+
+	attrs = append(attrs, semconv.DBSystemPostgreSQL)
+	attrs = append(attrs, t.opts.Attributes...)
+
+	if op != "" {
+		attrs = append(attrs, semconv.DBOperationKey.String(op))
+	}
+
+	if statement != "" {
+		attrs = append(attrs, semconv.DBStatementKey.String(statement))
+	}
+
+	span, ctx := tele.StartSpan(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return context.WithValue(ctx, startKey{}, &callInfo{span: span, start: time.Now(), operation: op, statement: statement})
+}
+
+// end finishes call started by start, context without call info belongs to someone else and its span is left as is
+func (t *Tracer) end(ctx context.Context, err error, attrs ...attribute.KeyValue) {
+	info, ok := ctx.Value(startKey{}).(*callInfo)
+	if !ok {
+		return
+	}
+
+	span := info.span
+	defer span.End()
+
+	span.SetAttributes(attrs...)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	duration := time.Since(info.start)
+	t.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationKey.String(info.operation),
+	))
+
+	if !t.opts.Log {
+		return
+	}
+
+	lvl := zapcore.DebugLevel
+	fields := []zap.Field{
+		tel.String("operation", info.operation),
+		tel.String("statement", info.statement),
+		tel.Duration("duration", duration),
+	}
+
+	if err != nil {
+		lvl = zapcore.ErrorLevel
+		fields = append(fields, tel.Error(err))
+	}
+
+	tel.FromCtx(ctx).Log(lvl, "pgx: "+strings.ToLower(info.operation), fields...)
+}
+
+func (t *Tracer) statement(sql string) string {
+	if t.opts.Sanitize == nil {
+		return sql
+	}
+
+	return t.opts.Sanitize(sql)
+}
+
+func (t *Tracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	op := operation(data.SQL)
+
+	return t.start(ctx, spanName(op), op, t.statement(data.SQL))
+}
+
+func (t *Tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.end(ctx, data.Err, RowsAffectedKey.Int64(data.CommandTag.RowsAffected()))
+}
+
+func (t *Tracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	size := 0
+	if data.Batch != nil {
+		size = data.Batch.Len()
+	}
+
+	return t.start(ctx, spanName(OperationBatch), OperationBatch, "",
+		attribute.Int("db.batch.size", size))
+}
+
+// TraceBatchQuery adds span event per query of batch, queries of batch have no own timing
+func (t *Tracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	attrs := []attribute.KeyValue{
+		semconv.DBOperationKey.String(operation(data.SQL)),
+		semconv.DBStatementKey.String(t.statement(data.SQL)),
+		RowsAffectedKey.Int64(data.CommandTag.RowsAffected()),
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil {
+		span.RecordError(data.Err, trace.WithAttributes(attrs...))
+		return
+	}
+
+	span.AddEvent("query", trace.WithAttributes(attrs...))
+}
+
+func (t *Tracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	t.end(ctx, data.Err)
+}
+
+func (t *Tracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	statement := "COPY " + data.TableName.Sanitize() + " (" + strings.Join(data.ColumnNames, ", ") + ") FROM STDIN"
+
+	return t.start(ctx, spanName(OperationCopy), OperationCopy, statement,
+		semconv.DBSQLTableKey.String(strings.Join(data.TableName, ".")))
+}
+
+func (t *Tracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	t.end(ctx, data.Err, RowsAffectedKey.Int64(data.CommandTag.RowsAffected()))
+}
+
+func (t *Tracer) TracePrepareStart(ctx context.Context, _ *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	return t.start(ctx, spanName(OperationPrepare), OperationPrepare, t.statement(data.SQL),
+		attribute.String("db.statement.name", data.Name))
+}
+
+func (t *Tracer) TracePrepareEnd(ctx context.Context, _ *pgx.Conn, data pgx.TracePrepareEndData) {
+	t.end(ctx, data.Err, attribute.Bool("db.statement.already_prepared", data.AlreadyPrepared))
+}
+
+func (t *Tracer) TraceConnectStart(ctx context.Context, data pgx.TraceConnectStartData) context.Context {
+	var attrs []attribute.KeyValue
+
+	if cfg := data.ConnConfig; cfg != nil {
+		attrs = append(attrs,
+			semconv.DBNameKey.String(cfg.Database),
+			semconv.DBUserKey.String(cfg.User),
+			semconv.NetPeerNameKey.String(cfg.Host),
+			semconv.NetPeerPortKey.Int(int(cfg.Port)),
+		)
+	}
+
+	return t.start(ctx, spanName(OperationConnect), OperationConnect, "", attrs...)
+}
+
+func (t *Tracer) TraceConnectEnd(ctx context.Context, data pgx.TraceConnectEndData) {
+	t.end(ctx, data.Err)
+}
+
+func spanName(op string) string {
+	if op == "" {
+		return "pgx.query"
+	}
+
+	return "pgx." + strings.ToLower(op)
+}
@@ -0,0 +1,103 @@
+package pgx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2"
+	"github.com/tel-io/tel/v2/teltest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTracer(t *testing.T) {
+	h := teltest.New(t)
+	tracer := NewTracer()
+
+	span, ctx := tel.FromCtx(h.Ctx()).StartSpan(h.Ctx(), "handler")
+
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT * FROM users WHERE id = 42"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+
+	qctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "update users set name = 'x'"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{Err: errors.New("deadlock")})
+
+	bctx := tracer.TraceBatchStart(ctx, nil, pgx.TraceBatchStartData{Batch: &pgx.Batch{}})
+	tracer.TraceBatchQuery(bctx, nil, pgx.TraceBatchQueryData{SQL: "DELETE FROM users WHERE id = 1"})
+	tracer.TraceBatchEnd(bctx, nil, pgx.TraceBatchEndData{})
+
+	cctx := tracer.TraceCopyFromStart(ctx, nil, pgx.TraceCopyFromStartData{
+		TableName:   pgx.Identifier{"public", "users"},
+		ColumnNames: []string{"id", "name"},
+	})
+	tracer.TraceCopyFromEnd(cctx, nil, pgx.TraceCopyFromEndData{CommandTag: pgconn.NewCommandTag("COPY 2")})
+
+	span.End()
+
+	t.Run("spans", func(t *testing.T) {
+		spans := h.Spans()
+
+		parent := spans.Name("handler").First()
+		require.NotNil(t, parent)
+		assert.Len(t, spans.ChildrenOf(parent), 4)
+
+		sel := spans.Name("pgx.select").First()
+		require.NotNil(t, sel)
+		assert.Len(t, spans.Name("pgx.select").With(
+			attribute.String("db.statement", "SELECT * FROM users WHERE id = ?"),
+			attribute.String("db.operation", "SELECT"),
+			RowsAffectedKey.Int64(1),
+		), 1)
+
+		upd := spans.Name("pgx.update").First()
+		require.NotNil(t, upd)
+		assert.Equal(t, codes.Error, upd.Status().Code)
+
+		batch := spans.Name("pgx.batch").First()
+		require.NotNil(t, batch)
+		require.Len(t, batch.Events(), 1)
+
+		assert.Len(t, spans.Name("pgx.copy").With(
+			attribute.String("db.statement", `COPY "public"."users" (id, name) FROM STDIN`),
+			RowsAffectedKey.Int64(2),
+		), 1)
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		assert.Len(t, h.Logs().Message("pgx: update").Level(zapcore.ErrorLevel), 1)
+		assert.Len(t, h.Logs().Message("pgx: select").Level(zapcore.DebugLevel), 1)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		m := h.Metrics()
+
+		for _, op := range []string{"SELECT", "UPDATE", OperationBatch, OperationCopy} {
+			count, ok := m.HistogramCount(MetricQueryDuration, attribute.String("db.operation", op))
+			assert.True(t, ok, op)
+			assert.Equal(t, uint64(1), count, op)
+		}
+	})
+}
+
+func TestTracer_EndWithoutStart(t *testing.T) {
+	h := teltest.New(t)
+	tracer := NewTracer()
+
+	span, ctx := tel.FromCtx(h.Ctx()).StartSpan(h.Ctx(), "handler")
+
+	// context of another call, parent span is left open
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("deadlock")})
+	assert.True(t, span.IsRecording())
+
+	span.End()
+
+	parent := h.Spans().Name("handler").First()
+	require.NotNil(t, parent)
+	assert.NotEqual(t, codes.Error, parent.Status().Code)
+	assert.Empty(t, h.Logs().Message("pgx: "))
+}
@@ -3,6 +3,7 @@ package cardinalitydetector
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,16 +14,39 @@ import (
 
 var _ Detector = (*detector)(nil)
 var _ Detector = (*noopDetector)(nil)
+var _ Detector = (*overflowDetector)(nil)
 
-var noopDetectorInstance = &noopDetector{} //nolint:gochecknoglobals
+var noopDetectorInstance = &noopDetector{}         //nolint:gochecknoglobals
+var overflowDetectorInstance = &overflowDetector{} //nolint:gochecknoglobals
 
 type Detector interface {
 	CheckAttrs(context.Context, []attribute.KeyValue) bool
+	// LimitAttrs applies Options.Mode to attrs: ok is false if measurement is dropped,
+	// limited has denied keys removed and high cardinality values replaced with OverflowValue, it's nil if attrs are kept
+	LimitAttrs(context.Context, []attribute.KeyValue) (limited []attribute.KeyValue, ok bool)
+	// Report returns state of attributes sorted by key
+	Report() []AttributeReport
 	Shutdown()
 }
 
+// IsOverflow reports whether detector is given to instrument exceeding MaxInstruments in ModeOverflow
+func IsOverflow(d Detector) bool {
+	_, ok := d.(*overflowDetector)
+
+	return ok
+}
+
+// New creates detector of instrument, the first of Options.Rules matching name is applied
 func New(ctx context.Context, name string, opts Options) Detector {
-	if !opts.Enable || opts.MaxCardinality <= 0 {
+	return newDetector(ctx, name, opts, opts.rule(name))
+}
+
+func newDetector(ctx context.Context, name string, opts Options, rule Rule) Detector {
+	if rule.MaxCardinality > 0 {
+		opts.MaxCardinality = rule.MaxCardinality
+	}
+
+	if !opts.Enable || rule.Exempt || (opts.MaxCardinality <= 0 && len(rule.Deny) == 0) {
 		return noopDetectorInstance
 	}
 
@@ -33,8 +57,11 @@ func New(ctx context.Context, name string, opts Options) Detector {
 	detector := &detector{
 		opts:            opts,
 		name:            name,
-		attrs:           make(map[string]map[string]struct{}),
+		attrs:           make(map[string]values),
 		highCardinality: make(map[string]struct{}),
+		allow:           keySet(rule.Allow),
+		deny:            keySet(rule.Deny),
+		windowStart:     time.Now(),
 	}
 
 	if opts.CheckInterval > 0 {
@@ -49,8 +76,11 @@ func New(ctx context.Context, name string, opts Options) Detector {
 type detector struct {
 	opts            Options
 	name            string
-	attrs           map[string]map[string]struct{}
+	attrs           map[string]values
 	highCardinality map[string]struct{}
+	allow           map[string]struct{}
+	deny            map[string]struct{}
+	windowStart     time.Time
 	checkTicker     *time.Ticker
 	checkDone       chan struct{}
 
@@ -126,40 +156,151 @@ func (d *detector) CheckAttrs(ctx context.Context, attrs []attribute.KeyValue) b
 	return ok
 }
 
-// Check implements HighCardinalityDetector.
-func (d *detector) check(key string, value string) (bool, []log.Attr) {
-	if uniqueValues, ok := d.attrs[key]; ok {
-		if _, ok := uniqueValues[value]; ok {
-			return true, nil
+// LimitAttrs implements Detector.
+func (d *detector) LimitAttrs(ctx context.Context, attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	limited := d.strip(attrs)
+	if limited != nil {
+		attrs = limited
+	}
+
+	if d.opts.Mode != ModeOverflow {
+		if !d.CheckAttrs(ctx, attrs) {
+			return nil, false
 		}
 
-		_, hasHighCardinality := d.highCardinality[key]
-		if hasHighCardinality {
-			return false, nil
+		return limited, true
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var details [][]log.Attr
+
+	d.mu.Lock()
+	for i, attr := range attrs {
+		ok, attrDetails := d.check(string(attr.Key), attr.Value.Emit())
+		if len(attrDetails) > 0 {
+			details = append(details, attrDetails)
 		}
 
-		ok := len(uniqueValues) < d.opts.MaxCardinality
-		var details []log.Attr
 		if ok {
-			uniqueValues[value] = struct{}{}
-		} else if !hasHighCardinality {
-			d.highCardinality[key] = struct{}{}
-
-			details = []log.Attr{
-				log.String("instrument_name", d.name),
-				log.String("attribute_name", key),
-				log.Int("max_cardinality", d.opts.MaxCardinality),
-				log.Int("attributes_size", len(d.attrs)),
-				log.String("last_value", value),
+			continue
+		}
+
+		// stripped attrs are already a copy
+		if limited == nil {
+			limited = make([]attribute.KeyValue, len(attrs))
+			copy(limited, attrs)
+		}
+
+		limited[i] = attribute.String(string(attr.Key), OverflowValue)
+	}
+	d.mu.Unlock()
+
+	for _, attrDetails := range details {
+		d.opts.Logger.Warn(
+			ctx,
+			"instrument has high cardinality for attribute",
+			attrDetails...,
+		)
+	}
+
+	return limited, true
+}
+
+// strip returns attrs without denied keys, nil if there are none
+func (d *detector) strip(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if len(d.deny) == 0 {
+		return nil
+	}
+
+	var stripped []attribute.KeyValue
+
+	for i, attr := range attrs {
+		if _, ok := d.deny[string(attr.Key)]; !ok {
+			if stripped != nil {
+				stripped = append(stripped, attr)
 			}
+
+			continue
+		}
+
+		if stripped == nil {
+			stripped = make([]attribute.KeyValue, i, len(attrs)-1)
+			copy(stripped, attrs[:i])
 		}
+	}
+
+	return stripped
+}
 
-		return ok, details
+// Check implements HighCardinalityDetector.
+func (d *detector) check(key string, value string) (bool, []log.Attr) {
+	// allowed keys aren't limited, denied ones are stripped by LimitAttrs
+	if _, ok := d.allow[key]; ok || d.opts.MaxCardinality <= 0 {
+		return true, nil
 	}
 
-	d.attrs[key] = map[string]struct{}{value: {}}
+	if _, ok := d.deny[key]; ok {
+		return true, nil
+	}
+
+	now := time.Now()
+
+	// attributes may go back to normal as values age out
+	if d.opts.Window > 0 && now.Sub(d.windowStart) >= d.opts.Window {
+		clear(d.highCardinality)
+		d.windowStart = now
+	}
+
+	uniqueValues, ok := d.attrs[key]
+	if !ok {
+		uniqueValues = newValues(d.opts, now)
+		d.attrs[key] = uniqueValues
+	}
 
-	return true, nil
+	if uniqueValues.add(value, now) {
+		return true, nil
+	}
+
+	if _, hasHighCardinality := d.highCardinality[key]; hasHighCardinality {
+		return false, nil
+	}
+
+	d.highCardinality[key] = struct{}{}
+
+	return false, []log.Attr{
+		log.String("instrument_name", d.name),
+		log.String("attribute_name", key),
+		log.Int("max_cardinality", d.opts.MaxCardinality),
+		log.Int("attributes_size", len(d.attrs)),
+		log.String("last_value", value),
+	}
+}
+
+// Report implements Detector.
+func (d *detector) Report() []AttributeReport {
+	now := time.Now()
+
+	d.mu.Lock()
+	report := make([]AttributeReport, 0, len(d.attrs))
+	for key, uniqueValues := range d.attrs {
+		_, blocked := d.highCardinality[key]
+		report = append(report, AttributeReport{
+			Key:     key,
+			Count:   uniqueValues.count(now),
+			Limit:   d.opts.MaxCardinality,
+			Blocked: blocked,
+		})
+	}
+	d.mu.Unlock()
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Key < report[j].Key
+	})
+
+	return report
 }
 
 // Shutdown implements CardinalityDetector.
@@ -187,3 +328,43 @@ func (*noopDetector) Shutdown() {}
 func (*noopDetector) CheckAttrs(_ context.Context, _ []attribute.KeyValue) bool {
 	return true
 }
+
+// Report implements Detector.
+func (*noopDetector) Report() []AttributeReport {
+	return nil
+}
+
+// LimitAttrs implements Detector.
+func (*noopDetector) LimitAttrs(_ context.Context, _ []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	return nil, true
+}
+
+// overflowDetector replaces all attribute values of instrument
+type overflowDetector struct{}
+
+// Shutdown implements Detector.
+func (*overflowDetector) Shutdown() {}
+
+// CheckAttrs implements Detector.
+func (*overflowDetector) CheckAttrs(_ context.Context, _ []attribute.KeyValue) bool {
+	return false
+}
+
+// Report implements Detector.
+func (*overflowDetector) Report() []AttributeReport {
+	return nil
+}
+
+// LimitAttrs implements Detector.
+func (*overflowDetector) LimitAttrs(_ context.Context, attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	if len(attrs) == 0 {
+		return nil, true
+	}
+
+	limited := make([]attribute.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		limited[i] = attribute.String(string(attr.Key), OverflowValue)
+	}
+
+	return limited, true
+}
@@ -82,3 +82,32 @@ func TestCardinalityDetector(t *testing.T) {
 		return true
 	})
 }
+
+func TestCardinalityDetector_Overflow(t *testing.T) {
+	assert := assert.New(t)
+
+	echo := make(chan log.Record, 1)
+	detector := New(nil, "foo", Options{
+		Enable:         true,
+		MaxCardinality: 1,
+		Mode:           ModeOverflow,
+		Logger:         log.NewLogger(log.NewEchoHandler(echo)),
+	})
+
+	limited, ok := detector.LimitAttrs(nil, []attribute.KeyValue{attribute.String("user", "a"), attribute.Int("code", 200)})
+	assert.True(ok)
+	assert.Nil(limited)
+
+	limited, ok = detector.LimitAttrs(nil, []attribute.KeyValue{attribute.String("user", "b"), attribute.Int("code", 200)})
+	assert.True(ok)
+	assert.Equal([]attribute.KeyValue{attribute.String("user", OverflowValue), attribute.Int("code", 200)}, limited)
+	assert.Equal("instrument has high cardinality for attribute", (<-echo).Message)
+
+	assert.False(detector.CheckAttrs(nil, []attribute.KeyValue{attribute.String("user", "c")}))
+
+	detector = New(nil, "foo", Options{Enable: true, MaxCardinality: 1, Mode: ModeDrop, Logger: log.NewLogger(log.NewEchoHandler(echo))})
+	_, _ = detector.LimitAttrs(nil, []attribute.KeyValue{attribute.String("user", "a")})
+	limited, ok = detector.LimitAttrs(nil, []attribute.KeyValue{attribute.String("user", "b")})
+	assert.False(ok)
+	assert.Nil(limited)
+}
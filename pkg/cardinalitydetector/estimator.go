@@ -0,0 +1,197 @@
+package cardinalitydetector
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// hllPrecision gives 1024 registers of one byte, standard error is about 3%
+const hllPrecision = 10
+
+// values counts distinct values of attribute within limit
+type values interface {
+	// add counts value, false means value exceeds limit
+	add(value string, now time.Time) bool
+	// count is number of distinct values
+	count(now time.Time) int
+}
+
+func newValues(opts Options, now time.Time) values {
+	// admitted values would be rejected by hll once limit is passed, so drop mode counts values exactly
+	if opts.Estimator == EstimatorHyperLogLog && opts.Mode == ModeOverflow {
+		v := &hllValues{limit: opts.MaxCardinality, window: opts.Window, start: now, union: newHyperLogLog()}
+		if v.window > 0 {
+			v.current = newHyperLogLog()
+		}
+
+		return v
+	}
+
+	return &exactValues{
+		limit:   opts.MaxCardinality,
+		window:  opts.Window,
+		start:   now,
+		current: make(map[string]struct{}),
+	}
+}
+
+// exactValues keeps up to limit values, with window values not seen during the previous window are forgotten
+type exactValues struct {
+	limit  int
+	window time.Duration
+	start  time.Time
+
+	current  map[string]struct{}
+	previous map[string]struct{}
+	// previousOnly is number of previous values not seen in current window
+	previousOnly int
+}
+
+func (v *exactValues) add(value string, now time.Time) bool {
+	v.rotate(now)
+
+	if _, ok := v.current[value]; ok {
+		return true
+	}
+
+	if _, ok := v.previous[value]; ok {
+		v.current[value] = struct{}{}
+		v.previousOnly--
+
+		return true
+	}
+
+	if v.count(now) >= v.limit {
+		return false
+	}
+
+	v.current[value] = struct{}{}
+
+	return true
+}
+
+func (v *exactValues) count(now time.Time) int {
+	v.rotate(now)
+
+	return len(v.current) + v.previousOnly
+}
+
+func (v *exactValues) rotate(now time.Time) {
+	if v.window <= 0 || now.Sub(v.start) < v.window {
+		return
+	}
+
+	v.previous, v.current = v.current, make(map[string]struct{}, len(v.current))
+	if now.Sub(v.start) >= 2*v.window {
+		v.previous = nil
+	}
+
+	v.previousOnly = len(v.previous)
+	v.start = now
+}
+
+// hllValues estimates number of values with fixed memory, union of current and previous window is estimated.
+// Values aren't stored, so once estimate exceeds limit any value is rejected until the window rolls, used in ModeOverflow only
+type hllValues struct {
+	limit  int
+	window time.Duration
+	start  time.Time
+
+	// current is kept for rotation only, it's nil without window
+	current *hyperLogLog
+	union   *hyperLogLog
+}
+
+func (v *hllValues) add(value string, now time.Time) bool {
+	v.rotate(now)
+
+	idx, rank := hllRegister(hash(value))
+	v.union.set(idx, rank)
+
+	if v.window > 0 {
+		v.current.set(idx, rank)
+	}
+
+	return v.count(now) <= v.limit
+}
+
+func (v *hllValues) count(now time.Time) int {
+	v.rotate(now)
+
+	return int(math.Round(v.union.estimate()))
+}
+
+func (v *hllValues) rotate(now time.Time) {
+	if v.window <= 0 || now.Sub(v.start) < v.window {
+		return
+	}
+
+	v.union = v.current
+	if now.Sub(v.start) >= 2*v.window {
+		v.union = newHyperLogLog()
+	}
+
+	v.current = newHyperLogLog()
+	v.start = now
+}
+
+// hash is FNV-1a mixed by splitmix64 finalizer, registers need well distributed high bits
+func hash(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+
+	return h
+}
+
+func hllRegister(hash uint64) (int, uint8) {
+	return int(hash >> (64 - hllPrecision)), uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1)) + 1)
+}
+
+// hyperLogLog is sketch of distinct count by Flajolet et al., sum of 2^-register is kept up to date
+type hyperLogLog struct {
+	registers [1 << hllPrecision]uint8
+	sum       float64
+	zeros     int
+}
+
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{sum: 1 << hllPrecision, zeros: 1 << hllPrecision}
+}
+
+func (h *hyperLogLog) set(idx int, rank uint8) {
+	old := h.registers[idx]
+	if rank <= old {
+		return
+	}
+
+	if old == 0 {
+		h.zeros--
+	}
+
+	h.sum += 1/float64(uint64(1)<<rank) - 1/float64(uint64(1)<<old)
+	h.registers[idx] = rank
+}
+
+func (h *hyperLogLog) estimate() float64 {
+	const m = float64(1 << hllPrecision)
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	e := alpha * m * m / h.sum
+
+	// linear counting is precise for small cardinalities
+	if e <= 2.5*m && h.zeros > 0 {
+		return m * math.Log(m/float64(h.zeros))
+	}
+
+	return e
+}
@@ -0,0 +1,92 @@
+package cardinalitydetector
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	for _, n := range []int{10, 100, 1000, 100000} {
+		v := newValues(Options{Estimator: EstimatorHyperLogLog, Mode: ModeOverflow, MaxCardinality: n}, time.Now())
+
+		for i := 0; i < n; i++ {
+			v.add("value-"+strconv.Itoa(i), time.Now())
+		}
+
+		assert.InEpsilon(t, n, v.count(time.Now()), 0.03, n)
+	}
+}
+
+func TestValues(t *testing.T) {
+	start := time.Unix(0, 0)
+
+	for _, estimator := range []string{EstimatorExact, EstimatorHyperLogLog} {
+		t.Run(estimator, func(t *testing.T) {
+			v := newValues(Options{Estimator: estimator, Mode: ModeOverflow, MaxCardinality: 3, Window: time.Minute}, start)
+
+			assert.True(t, v.add("a", start))
+			assert.True(t, v.add("b", start))
+			assert.True(t, v.add("c", start))
+			assert.False(t, v.add("d", start))
+			assert.InDelta(t, 3, v.count(start), 1)
+
+			// previous window values are still counted
+			now := start.Add(time.Minute)
+			assert.False(t, v.add("e", now))
+
+			now = now.Add(time.Minute)
+			assert.True(t, v.add("e", now))
+			assert.True(t, v.add("f", now))
+
+			// no values for two windows
+			now = now.Add(2 * time.Minute)
+			assert.Equal(t, 0, v.count(now))
+		})
+	}
+
+	t.Run("hll in drop mode keeps known values", func(t *testing.T) {
+		v := newValues(Options{Estimator: EstimatorHyperLogLog, Mode: ModeDrop, MaxCardinality: 2}, start)
+
+		assert.IsType(t, &exactValues{}, v)
+		assert.True(t, v.add("a", start))
+		assert.True(t, v.add("b", start))
+		assert.False(t, v.add("c", start))
+		assert.True(t, v.add("a", start))
+	})
+
+	t.Run("exact keeps known values", func(t *testing.T) {
+		v := newValues(Options{Estimator: EstimatorExact, MaxCardinality: 2}, start)
+
+		assert.True(t, v.add("a", start))
+		assert.True(t, v.add("b", start))
+		assert.False(t, v.add("c", start))
+		assert.True(t, v.add("a", start.Add(time.Hour)))
+		assert.Equal(t, 2, v.count(start))
+	})
+
+	t.Run("exact window", func(t *testing.T) {
+		v := newValues(Options{Estimator: EstimatorExact, MaxCardinality: 2, Window: time.Minute}, start)
+
+		assert.True(t, v.add("a", start))
+		assert.True(t, v.add("b", start))
+
+		now := start.Add(time.Minute)
+		assert.True(t, v.add("a", now))
+		assert.Equal(t, 2, v.count(now))
+
+		// b ages out
+		now = now.Add(time.Minute)
+		assert.Equal(t, 1, v.count(now))
+		assert.True(t, v.add("c", now))
+	})
+}
+
+func TestValidateEstimator(t *testing.T) {
+	assert.NoError(t, ValidateEstimator(EstimatorExact, ModeDrop))
+	assert.NoError(t, ValidateEstimator(EstimatorHyperLogLog, ModeOverflow))
+	assert.ErrorIs(t, ValidateEstimator(EstimatorHyperLogLog, ModeDrop), ErrEstimatorMode)
+	assert.ErrorIs(t, ValidateEstimator("sketch", ModeOverflow), ErrUnknownEstimator)
+}
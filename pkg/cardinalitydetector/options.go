@@ -1,12 +1,74 @@
 package cardinalitydetector
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/tel-io/tel/v2/pkg/global"
 	"github.com/tel-io/tel/v2/pkg/log"
 )
 
+// Modes of handling measurements which exceed limits
+const (
+	// ModeDrop drops measurements with high cardinality attributes, instruments exceeding MaxInstruments aren't created
+	ModeDrop = "drop"
+	// ModeOverflow replaces high cardinality attribute values with OverflowValue, so totals stay correct.
+	// Instruments exceeding MaxInstruments get all attribute values replaced
+	ModeOverflow = "overflow"
+)
+
+// OverflowValue replaces attribute values in ModeOverflow
+const OverflowValue = "__other__"
+
+// OverflowInstrument returns name of single instrument of kind (e.g. int64_counter) which replaces instruments
+// exceeding MaxInstruments in ModeOverflow. Instrument name must start with letter, so OverflowValue isn't used as is
+func OverflowInstrument(kind string) string {
+	return "tel.cardinality." + OverflowValue + "." + kind
+}
+
+// Estimators of distinct attribute values
+const (
+	// EstimatorExact keeps up to MaxCardinality values of every attribute, known values always pass
+	EstimatorExact = "exact"
+	// EstimatorHyperLogLog estimates number of values with fixed memory of 1KB per attribute (2KB with Window),
+	// values aren't kept, so all values of attribute are limited once estimate exceeds MaxCardinality.
+	// It's allowed in ModeOverflow only where totals stay correct, EstimatorExact is used in ModeDrop
+	EstimatorHyperLogLog = "hll"
+)
+
+var (
+	ErrUnknownMode      = errors.New("unknown cardinality detector mode")
+	ErrUnknownEstimator = errors.New("unknown cardinality detector estimator")
+	ErrEstimatorMode    = errors.New("cardinality detector estimator requires overflow mode")
+)
+
+// ValidateMode checks mode is ModeDrop or ModeOverflow
+func ValidateMode(mode string) error {
+	switch mode {
+	case ModeDrop, ModeOverflow:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownMode, mode)
+}
+
+// ValidateEstimator checks estimator is EstimatorExact or EstimatorHyperLogLog, the latter requires ModeOverflow
+func ValidateEstimator(estimator, mode string) error {
+	switch estimator {
+	case EstimatorExact:
+		return nil
+	case EstimatorHyperLogLog:
+		if mode != ModeOverflow {
+			return fmt.Errorf("%w: %q in mode %q", ErrEstimatorMode, estimator, mode)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownEstimator, estimator)
+}
+
 type Option func(*Options)
 
 func DefaultOptions() Options {
@@ -14,6 +76,8 @@ func DefaultOptions() Options {
 		Enable:         true,
 		MaxCardinality: 100,
 		MaxInstruments: 500,
+		Mode:           ModeDrop,
+		Estimator:      EstimatorExact,
 		Logger:         global.GetLogger(),
 	}
 }
@@ -32,6 +96,10 @@ type Options struct {
 	MaxCardinality int
 	MaxInstruments int
 	CheckInterval  time.Duration
+	Mode           string
+	Estimator      string
+	Window         time.Duration // values not seen for one-two windows are forgotten, 0 keeps them
+	Rules          []Rule        // per instrument overrides, the first matching rule is used
 	Logger         log.Logger
 }
 
@@ -59,6 +127,31 @@ func WithCheckInterval(interval time.Duration) Option {
 	}
 }
 
+func WithMode(mode string) Option {
+	return func(opts *Options) {
+		opts.Mode = mode
+	}
+}
+
+func WithEstimator(estimator string) Option {
+	return func(opts *Options) {
+		opts.Estimator = estimator
+	}
+}
+
+func WithWindow(window time.Duration) Option {
+	return func(opts *Options) {
+		opts.Window = window
+	}
+}
+
+// WithRules appends rules, rules added earlier take precedence
+func WithRules(rules ...Rule) Option {
+	return func(opts *Options) {
+		opts.Rules = append(opts.Rules, rules...)
+	}
+}
+
 func WithLogger(logger log.Logger) Option {
 	return func(opts *Options) {
 		opts.Logger = logger
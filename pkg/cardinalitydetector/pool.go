@@ -3,6 +3,8 @@ package cardinalitydetector
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +16,8 @@ var noopPoolInstance = &noopPool{} //nolint:gochecknoglobals
 
 type Pool interface {
 	Lookup(context.Context, string) (Detector, bool)
+	// Report returns state of instruments sorted by name, scope is empty if detection is disabled
+	Report() ScopeReport
 	Shutdown()
 }
 
@@ -111,12 +115,21 @@ func (p *cardinalityDetectorPool) Lookup(ctx context.Context, name string) (Dete
 }
 
 func (p *cardinalityDetectorPool) lookup(name string) (Detector, bool, []log.Attr) {
+	rule := p.opts.rule(name)
+	if rule.Exempt {
+		return noopDetectorInstance, true, nil
+	}
+
 	p.mu.Lock()
 	limitDetected := p.limitDetected
 	_, nameFound := p.names[name]
 	p.mu.Unlock()
 
 	if limitDetected && !nameFound {
+		if p.opts.Mode == ModeOverflow {
+			return overflowDetectorInstance, true, nil
+		}
+
 		return nil, false, nil
 	}
 
@@ -127,7 +140,7 @@ func (p *cardinalityDetectorPool) lookup(name string) (Detector, bool, []log.Att
 		return detector.(Detector), true, nil //nolint:forcetypeassert
 	}
 
-	detectorNew := New(p.stopCtx, detectorName, p.opts)
+	detectorNew := newDetector(p.stopCtx, detectorName, p.opts, rule)
 	detector, loaded := p.pool.LoadOrStore(detectorName, detectorNew)
 
 	var details []log.Attr
@@ -152,6 +165,34 @@ func (p *cardinalityDetectorPool) lookup(name string) (Detector, bool, []log.Att
 	return detector.(Detector), true, details //nolint:forcetypeassert
 }
 
+func (p *cardinalityDetectorPool) Report() ScopeReport {
+	p.mu.Lock()
+	report := ScopeReport{
+		Scope:   p.instrumentationName,
+		Count:   len(p.names),
+		Limit:   p.opts.MaxInstruments,
+		Blocked: p.limitDetected,
+	}
+	p.mu.Unlock()
+
+	prefix := p.instrumentationName + "/"
+
+	p.pool.Range(func(name, detector interface{}) bool {
+		report.Instruments = append(report.Instruments, InstrumentReport{
+			Name:       strings.TrimPrefix(name.(string), prefix), //nolint:forcetypeassert
+			Attributes: detector.(Detector).Report(),              //nolint:forcetypeassert
+		})
+
+		return true
+	})
+
+	sort.Slice(report.Instruments, func(i, j int) bool {
+		return report.Instruments[i].Name < report.Instruments[j].Name
+	})
+
+	return report
+}
+
 func (p *cardinalityDetectorPool) Shutdown() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -180,6 +221,11 @@ type noopPool struct{}
 // Shutdown implements CardinalityDetector.
 func (*noopPool) Shutdown() {}
 
+// Report implements Pool.
+func (*noopPool) Report() ScopeReport {
+	return ScopeReport{}
+}
+
 // CheckAttrs implements HighCardinalityDetector.
 func (*noopPool) Lookup(_ context.Context, _ string) (Detector, bool) {
 	return noopDetectorInstance, true
@@ -51,3 +51,26 @@ func TestCardinalityDetectorPool(t *testing.T) {
 		return true
 	})
 }
+
+func TestCardinalityDetectorPool_Overflow(t *testing.T) {
+	assert := assert.New(t)
+
+	pool := NewPool(nil, "instr/foo", Options{
+		Enable:         true,
+		MaxCardinality: 2,
+		MaxInstruments: 1,
+		Mode:           ModeOverflow,
+		Logger:         log.NewLogger(log.NewEchoHandler(make(chan log.Record, 1))),
+	})
+
+	cd, ok := pool.Lookup(nil, "foo")
+	assert.True(ok)
+	assert.False(IsOverflow(cd))
+
+	cd, ok = pool.Lookup(nil, "bar")
+	assert.True(ok)
+	assert.True(IsOverflow(cd))
+
+	assert.ErrorIs(ValidateMode("sample"), ErrUnknownMode)
+	assert.NoError(ValidateMode(ModeOverflow))
+}
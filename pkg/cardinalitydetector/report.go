@@ -0,0 +1,147 @@
+package cardinalitydetector
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metrics of cardinality reports
+const (
+	// MetricInstruments is number of instruments of scope
+	MetricInstruments = "tel.cardinality.instruments"
+	// MetricInstrumentsLimit is MaxInstruments of scope
+	MetricInstrumentsLimit = "tel.cardinality.instruments.limit"
+	// MetricInstrumentsBlocked is 1 if scope reached MaxInstruments
+	MetricInstrumentsBlocked = "tel.cardinality.instruments.blocked"
+	// MetricValues is number of distinct values of instrument attribute
+	MetricValues = "tel.cardinality.values"
+	// MetricValuesLimit is limit of distinct values of instrument attribute
+	MetricValuesLimit = "tel.cardinality.values.limit"
+	// MetricValuesBlocked is 1 if attribute reached limit
+	MetricValuesBlocked = "tel.cardinality.values.blocked"
+)
+
+const (
+	SignalKey     = attribute.Key("signal")
+	ScopeKey      = attribute.Key("scope")
+	InstrumentKey = attribute.Key("instrument")
+	AttributeKey  = attribute.Key("attribute")
+)
+
+const instrumentationName = "github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+
+// AttributeReport is state of instrument attribute
+type AttributeReport struct {
+	Key     string `json:"key"`
+	Count   int    `json:"count"`
+	Limit   int    `json:"limit"`
+	Blocked bool   `json:"blocked"`
+}
+
+// InstrumentReport is state of instrument (span name for tracers)
+type InstrumentReport struct {
+	Name       string            `json:"name"`
+	Attributes []AttributeReport `json:"attributes,omitempty"`
+}
+
+// ScopeReport is state of instrumentation scope
+type ScopeReport struct {
+	Scope string `json:"scope"`
+	Count int    `json:"count"`
+	Limit int    `json:"limit"`
+	// Blocked is true if scope reached limit of instruments
+	Blocked     bool               `json:"blocked"`
+	Instruments []InstrumentReport `json:"instruments,omitempty"`
+}
+
+// Reporter is provider of meters or tracers with cardinality detection
+type Reporter interface {
+	CardinalityReport() []ScopeReport
+}
+
+// SortReports sorts scope reports by scope name
+func SortReports(reports []ScopeReport) {
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Scope < reports[j].Scope
+	})
+}
+
+// RegisterMetrics exposes reports as gauges with signal attribute.
+// Provider without cardinality detection should be used, otherwise gauges are limited by themselves
+func RegisterMetrics(mp metric.MeterProvider, signal string, r Reporter) error {
+	meter := mp.Meter(instrumentationName)
+
+	instruments, err := meter.Int64ObservableGauge(MetricInstruments,
+		metric.WithDescription("Number of instruments of instrumentation scope"))
+	if err != nil {
+		return err
+	}
+
+	instrumentsLimit, err := meter.Int64ObservableGauge(MetricInstrumentsLimit,
+		metric.WithDescription("Limit of instruments of instrumentation scope"))
+	if err != nil {
+		return err
+	}
+
+	instrumentsBlocked, err := meter.Int64ObservableGauge(MetricInstrumentsBlocked,
+		metric.WithDescription("Instrumentation scope reached limit of instruments"))
+	if err != nil {
+		return err
+	}
+
+	values, err := meter.Int64ObservableGauge(MetricValues,
+		metric.WithDescription("Number of distinct values of instrument attribute"))
+	if err != nil {
+		return err
+	}
+
+	valuesLimit, err := meter.Int64ObservableGauge(MetricValuesLimit,
+		metric.WithDescription("Limit of distinct values of instrument attribute"))
+	if err != nil {
+		return err
+	}
+
+	valuesBlocked, err := meter.Int64ObservableGauge(MetricValuesBlocked,
+		metric.WithDescription("Instrument attribute reached limit of distinct values"))
+	if err != nil {
+		return err
+	}
+
+	signalAttr := SignalKey.String(signal)
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, scope := range r.CardinalityReport() {
+			scopeAttrs := metric.WithAttributes(signalAttr, ScopeKey.String(scope.Scope))
+
+			o.ObserveInt64(instruments, int64(scope.Count), scopeAttrs)
+			o.ObserveInt64(instrumentsLimit, int64(scope.Limit), scopeAttrs)
+			o.ObserveInt64(instrumentsBlocked, boolInt(scope.Blocked), scopeAttrs)
+
+			for _, instrument := range scope.Instruments {
+				for _, attr := range instrument.Attributes {
+					attrs := metric.WithAttributes(signalAttr, ScopeKey.String(scope.Scope),
+						InstrumentKey.String(instrument.Name), AttributeKey.String(attr.Key))
+
+					o.ObserveInt64(values, int64(attr.Count), attrs)
+					o.ObserveInt64(valuesLimit, int64(attr.Limit), attrs)
+					o.ObserveInt64(valuesBlocked, boolInt(attr.Blocked), attrs)
+				}
+			}
+		}
+
+		return nil
+	}, instruments, instrumentsLimit, instrumentsBlocked, values, valuesLimit, valuesBlocked)
+
+	return err
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
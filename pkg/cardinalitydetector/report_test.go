@@ -0,0 +1,93 @@
+package cardinalitydetector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCardinalityDetectorPool_Report(t *testing.T) {
+	pool := NewPool(nil, "instr/foo", Options{
+		Enable:         true,
+		MaxCardinality: 2,
+		MaxInstruments: 2,
+		Logger:         log.NewLogger(log.NewEchoHandler(make(chan log.Record, 10))),
+	})
+
+	cd, _ := pool.Lookup(nil, "foo")
+	for _, v := range []string{"1", "2", "3"} {
+		cd.CheckAttrs(nil, []attribute.KeyValue{attribute.String("user_id", v), attribute.String("method", "GET")})
+	}
+
+	pool.Lookup(nil, "bar")
+	pool.Lookup(nil, "baz")
+
+	assert.Equal(t, ScopeReport{
+		Scope:   "instr/foo",
+		Count:   2,
+		Limit:   2,
+		Blocked: true,
+		Instruments: []InstrumentReport{
+			{Name: "bar", Attributes: []AttributeReport{}},
+			{Name: "foo", Attributes: []AttributeReport{
+				{Key: "method", Count: 1, Limit: 2},
+				{Key: "user_id", Count: 2, Limit: 2, Blocked: true},
+			}},
+		},
+	}, pool.Report())
+
+	assert.Equal(t, ScopeReport{}, NewPool(nil, "instr/foo", Options{}).Report())
+}
+
+type reporterFunc func() []ScopeReport
+
+func (f reporterFunc) CardinalityReport() []ScopeReport {
+	return f()
+}
+
+func TestRegisterMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	err := RegisterMetrics(mp, "metrics", reporterFunc(func() []ScopeReport {
+		return []ScopeReport{{
+			Scope: "app",
+			Count: 1,
+			Limit: 10,
+			Instruments: []InstrumentReport{{
+				Name:       "requests",
+				Attributes: []AttributeReport{{Key: "user_id", Count: 5, Limit: 5, Blocked: true}},
+			}},
+		}}
+	}))
+	require.NoError(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	values := make(map[string]int64)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints { //nolint:forcetypeassert
+			signal, _ := dp.Attributes.Value(SignalKey)
+			assert.Equal(t, "metrics", signal.AsString())
+
+			values[m.Name] = dp.Value
+		}
+	}
+
+	assert.Equal(t, map[string]int64{
+		MetricInstruments:        1,
+		MetricInstrumentsLimit:   10,
+		MetricInstrumentsBlocked: 0,
+		MetricValues:             5,
+		MetricValuesLimit:        5,
+		MetricValuesBlocked:      1,
+	}, values)
+}
@@ -0,0 +1,135 @@
+package cardinalitydetector
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tel-io/tel/v2/pkg/glob"
+)
+
+var ErrInvalidRule = errors.New("invalid cardinality detector rule")
+
+// Settings of rules
+const (
+	SettingLimit  = "limit"
+	SettingAllow  = "allow"
+	SettingDeny   = "deny"
+	SettingExempt = "exempt"
+)
+
+// Rule overrides Options of instruments matching Instrument, the first matching rule is used
+type Rule struct {
+	// Instrument is glob of instrument name (span name for tracers) where * matches any sequence and ? any char
+	Instrument string
+	// MaxCardinality of instrument attributes, 0 keeps Options.MaxCardinality
+	MaxCardinality int
+	// Allow are attribute keys which are never limited
+	Allow []string
+	// Deny are attribute keys removed from measurements by LimitAttrs
+	Deny []string
+	// Exempt instrument isn't checked and doesn't count to MaxInstruments
+	Exempt bool
+}
+
+func (o Options) rule(name string) Rule {
+	for _, r := range o.Rules {
+		if glob.Match(r.Instrument, name) {
+			return r
+		}
+	}
+
+	return Rule{}
+}
+
+// ParseRules parses rules separated by ; or new line, lines starting with # are comments.
+// Rule is <instrument glob>=><setting>,<setting> where setting is limit:<n>, allow:<key>|<key>, deny:<key>|<key> or exempt:
+//
+//	http.server.duration=>limit:1000,deny:user_id; internal.*=>exempt
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		for _, raw := range strings.Split(line, ";") {
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+
+			r, err := parseRule(raw)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %w", raw, err)
+			}
+
+			rules = append(rules, r)
+		}
+	}
+
+	return rules, nil
+}
+
+func parseRule(raw string) (Rule, error) {
+	instrument, settings, ok := strings.Cut(raw, "=>")
+	if !ok {
+		return Rule{}, fmt.Errorf("%w: missing =>", ErrInvalidRule)
+	}
+
+	r := Rule{Instrument: strings.TrimSpace(instrument)}
+	if r.Instrument == "" {
+		return r, fmt.Errorf("%w: empty instrument", ErrInvalidRule)
+	}
+
+	for _, setting := range strings.Split(settings, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(setting), ":")
+
+		switch name {
+		case SettingLimit:
+			limit, err := strconv.Atoi(arg)
+			if err != nil || limit <= 0 {
+				return r, fmt.Errorf("%w: limit %q", ErrInvalidRule, arg)
+			}
+
+			r.MaxCardinality = limit
+		case SettingAllow:
+			r.Allow = append(r.Allow, parseKeys(arg)...)
+		case SettingDeny:
+			r.Deny = append(r.Deny, parseKeys(arg)...)
+		case SettingExempt:
+			r.Exempt = true
+		default:
+			return r, fmt.Errorf("%w: unknown setting %q", ErrInvalidRule, name)
+		}
+	}
+
+	return r, nil
+}
+
+func parseKeys(s string) []string {
+	var keys []string
+
+	for _, key := range strings.Split(s, "|") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func keySet(keys []string) map[string]struct{} {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+
+	return set
+}
@@ -0,0 +1,100 @@
+package cardinalitydetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(`
+# routes of http server
+http.server.*=>limit:1000, allow:http.method, deny:user_id|session_id
+internal.*=>exempt; db.*=>limit:10
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Instrument: "http.server.*", MaxCardinality: 1000, Allow: []string{"http.method"}, Deny: []string{"user_id", "session_id"}},
+		{Instrument: "internal.*", Exempt: true},
+		{Instrument: "db.*", MaxCardinality: 10},
+	}, rules)
+
+	for _, s := range []string{"http.*", "=>exempt", "http.*=>limit:0", "http.*=>limit:many", "http.*=>ignore"} {
+		_, err := ParseRules(s)
+		assert.ErrorIs(t, err, ErrInvalidRule, s)
+	}
+}
+
+func TestRules(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := Options{
+		Enable:         true,
+		MaxCardinality: 1,
+		MaxInstruments: 1,
+		Mode:           ModeDrop,
+		Logger:         log.NewLogger(log.NewEchoHandler(make(chan log.Record, 10))),
+		Rules: []Rule{
+			{Instrument: "internal.*", Exempt: true},
+			{Instrument: "http.*", MaxCardinality: 2, Allow: []string{"method"}, Deny: []string{"user_id"}},
+		},
+	}
+
+	pool := NewPool(nil, "instr/foo", opts)
+
+	// exempt instruments don't count to MaxInstruments
+	cd, ok := pool.Lookup(nil, "internal.queue")
+	assert.True(ok)
+	assert.True(cd.CheckAttrs(nil, []attribute.KeyValue{attribute.Int("id", 1)}))
+	assert.True(cd.CheckAttrs(nil, []attribute.KeyValue{attribute.Int("id", 2)}))
+
+	cd, ok = pool.Lookup(nil, "http.requests")
+	assert.True(ok)
+
+	for _, route := range []string{"/a", "/b"} {
+		limited, ok := cd.LimitAttrs(nil, []attribute.KeyValue{
+			attribute.String("route", route),
+			attribute.String("user_id", route),
+			attribute.String("method", route),
+		})
+		assert.True(ok)
+		assert.Equal([]attribute.KeyValue{attribute.String("route", route), attribute.String("method", route)}, limited)
+	}
+
+	limited, ok := cd.LimitAttrs(nil, []attribute.KeyValue{attribute.String("route", "/c")})
+	assert.False(ok)
+	assert.Nil(limited)
+
+	limited, ok = cd.LimitAttrs(nil, []attribute.KeyValue{attribute.String("method", "PUT")})
+	assert.True(ok)
+	assert.Nil(limited)
+
+	_, ok = pool.Lookup(nil, "db.queries")
+	assert.False(ok)
+
+	// overflow keeps positions of stripped attrs
+	opts.Mode = ModeOverflow
+	cd = New(nil, "http.requests", opts)
+
+	limited, _ = cd.LimitAttrs(nil, []attribute.KeyValue{attribute.String("user_id", "1"), attribute.String("route", "/a")})
+	assert.Equal([]attribute.KeyValue{attribute.String("route", "/a")}, limited)
+
+	limited, _ = cd.LimitAttrs(nil, []attribute.KeyValue{attribute.String("user_id", "1"), attribute.String("route", "/b")})
+	assert.Equal([]attribute.KeyValue{attribute.String("route", "/b")}, limited)
+
+	limited, _ = cd.LimitAttrs(nil, []attribute.KeyValue{attribute.String("user_id", "1"), attribute.String("route", "/c")})
+	assert.Equal([]attribute.KeyValue{attribute.String("route", OverflowValue)}, limited)
+
+	// deny works without limit
+	opts.MaxCardinality = 0
+	opts.Rules = []Rule{{Instrument: "*", Deny: []string{"user_id"}}}
+	cd = New(nil, "http.requests", opts)
+
+	limited, ok = cd.LimitAttrs(nil, []attribute.KeyValue{attribute.String("user_id", "1")})
+	assert.True(ok)
+	assert.Empty(limited)
+	assert.NotNil(limited)
+}
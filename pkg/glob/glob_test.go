@@ -0,0 +1,16 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	assert.True(t, Match("*", ""))
+	assert.True(t, Match("/api/*/items", "/api/v1/users/items"))
+	assert.True(t, Match("GET /user?", "GET /users"))
+	assert.True(t, Match("http.server.*", "http.server.duration"))
+	assert.False(t, Match("/health", "/healthz"))
+	assert.False(t, Match("/api/*/items", "/api/v1/users"))
+}
@@ -0,0 +1,185 @@
+// Package loglevel keeps atomic log levels of logger outputs which could be changed at runtime,
+// temporary changes revert to default level after TTL.
+package loglevel
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Outputs of tel logger
+const (
+	OutputStdout = "stdout"
+	OutputOTLP   = "otlp"
+)
+
+var ErrUnknownOutput = errors.New("unknown log output")
+
+// State of output level
+type State struct {
+	Output string        `json:"output"`
+	Level  zapcore.Level `json:"level"`
+	// Default is level which temporary level reverts to
+	Default zapcore.Level `json:"default"`
+	// Until is time of revert, nil for permanent level
+	Until *time.Time `json:"until,omitempty"`
+}
+
+type output struct {
+	// mu is lock of Levels, revert of temporary level takes it
+	mu *sync.Mutex
+
+	level zap.AtomicLevel
+	base  zapcore.Level
+	until time.Time
+	timer *time.Timer
+}
+
+// Levels is registry of output levels, safe for concurrent use
+type Levels struct {
+	mu      sync.Mutex
+	outputs map[string]*output
+}
+
+func New() *Levels {
+	return &Levels{outputs: make(map[string]*output)}
+}
+
+// Add registers output with default level and returns its atomic level for logger cores,
+// already registered output keeps its atomic level and gets new default
+func (l *Levels) Add(name string, lvl zapcore.Level) zap.AtomicLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if o, ok := l.outputs[name]; ok {
+		o.stop()
+		o.base = lvl
+		o.level.SetLevel(lvl)
+
+		return o.level
+	}
+
+	o := &output{mu: &l.mu, level: zap.NewAtomicLevelAt(lvl), base: lvl}
+	l.outputs[name] = o
+
+	return o.level
+}
+
+// Level returns atomic level of output
+func (l *Levels) Level(name string) (zap.AtomicLevel, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	o, ok := l.outputs[name]
+	if !ok {
+		return zap.AtomicLevel{}, false
+	}
+
+	return o.level, true
+}
+
+// Set changes level of output, empty name means all outputs.
+// With positive ttl level reverts to default after ttl, otherwise level becomes new default
+func (l *Levels) Set(name string, lvl zapcore.Level, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if name == "" {
+		for _, o := range l.outputs {
+			o.set(lvl, ttl)
+		}
+
+		return nil
+	}
+
+	o, ok := l.outputs[name]
+	if !ok {
+		return errors.WithMessagef(ErrUnknownOutput, "%q", name)
+	}
+
+	o.set(lvl, ttl)
+
+	return nil
+}
+
+// States returns current levels sorted by output name
+func (l *Levels) States() []State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]State, 0, len(l.outputs))
+
+	for name, o := range l.outputs {
+		s := State{Output: name, Level: o.level.Level(), Default: o.base}
+
+		if o.timer != nil {
+			until := o.until
+			s.Until = &until
+		}
+
+		out = append(out, s)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Output < out[j].Output })
+
+	return out
+}
+
+// Min returns the most verbose level among outputs, InfoLevel if there are no outputs
+func (l *Levels) Min() zapcore.Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.outputs) == 0 {
+		return zapcore.InfoLevel
+	}
+
+	lvl := zapcore.InvalidLevel
+	for _, o := range l.outputs {
+		if v := o.level.Level(); v < lvl {
+			lvl = v
+		}
+	}
+
+	return lvl
+}
+
+// set should be called under lock of Levels
+func (o *output) set(lvl zapcore.Level, ttl time.Duration) {
+	o.stop()
+	o.level.SetLevel(lvl)
+
+	if ttl <= 0 {
+		o.base = lvl
+		return
+	}
+
+	o.until = time.Now().Add(ttl)
+
+	var timer *time.Timer
+
+	timer = time.AfterFunc(ttl, func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+
+		// level could be changed meanwhile, only own timer reverts
+		if o.timer == timer {
+			o.level.SetLevel(o.base)
+			o.timer = nil
+		}
+	})
+
+	o.timer = timer
+}
+
+func (o *output) stop() {
+	if o.timer != nil {
+		o.timer.Stop()
+		o.timer = nil
+	}
+}
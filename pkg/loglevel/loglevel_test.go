@@ -0,0 +1,53 @@
+package loglevel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevels(t *testing.T) {
+	l := New()
+	stdout := l.Add(OutputStdout, zapcore.InfoLevel)
+	otlp := l.Add(OutputOTLP, zapcore.WarnLevel)
+
+	assert.Equal(t, zapcore.InfoLevel, l.Min())
+
+	t.Run("unknown output", func(t *testing.T) {
+		assert.ErrorIs(t, l.Set("file", zapcore.DebugLevel, 0), ErrUnknownOutput)
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		require.NoError(t, l.Set(OutputOTLP, zapcore.ErrorLevel, 0))
+
+		assert.Equal(t, zapcore.ErrorLevel, otlp.Level())
+		assert.Equal(t, []State{
+			{Output: OutputOTLP, Level: zapcore.ErrorLevel, Default: zapcore.ErrorLevel},
+			{Output: OutputStdout, Level: zapcore.InfoLevel, Default: zapcore.InfoLevel},
+		}, l.States())
+	})
+
+	t.Run("ttl reverts", func(t *testing.T) {
+		require.NoError(t, l.Set("", zapcore.DebugLevel, 50*time.Millisecond))
+
+		assert.Equal(t, zapcore.DebugLevel, stdout.Level())
+		assert.Equal(t, zapcore.DebugLevel, otlp.Level())
+		assert.NotNil(t, l.States()[0].Until)
+
+		assert.Eventually(t, func() bool {
+			return stdout.Level() == zapcore.InfoLevel && otlp.Level() == zapcore.ErrorLevel
+		}, time.Second, 10*time.Millisecond)
+		assert.Nil(t, l.States()[0].Until)
+	})
+
+	t.Run("new level cancels revert", func(t *testing.T) {
+		require.NoError(t, l.Set(OutputStdout, zapcore.DebugLevel, 20*time.Millisecond))
+		require.NoError(t, l.Set(OutputStdout, zapcore.WarnLevel, 0))
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, zapcore.WarnLevel, stdout.Level())
+	})
+}
@@ -0,0 +1,149 @@
+package logtransform
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+// wrapOverhead reserves bytes for the tag and length prefixes of
+// ExportLogsServiceRequest -> ResourceLogs -> ScopeLogs nesting,
+// each prefix takes one byte of tag and up to 5 bytes of length varint
+const wrapOverhead = 3 * (1 + 5)
+
+// Size returns encoded size of single log record as it would be placed into export request
+func Size(rec *logspb.LogRecord) int {
+	return 1 + protowire.SizeBytes(proto.Size(rec))
+}
+
+// Split divides rl into chunks which encoded export request does not exceed maxBytes.
+// Record which alone exceeds maxBytes is placed into own chunk, it's up to collector to reject it.
+// maxBytes <= 0 disables splitting.
+func Split(rl *logspb.ResourceLogs, maxBytes int) []*logspb.ResourceLogs {
+	if rl == nil || maxBytes <= 0 {
+		return []*logspb.ResourceLogs{rl}
+	}
+
+	if proto.Size(rl)+wrapOverhead <= maxBytes {
+		return []*logspb.ResourceLogs{rl}
+	}
+
+	base := proto.Size(&logspb.ResourceLogs{Resource: rl.Resource, SchemaUrl: rl.SchemaUrl}) + wrapOverhead
+
+	var out []*logspb.ResourceLogs
+
+	for _, sl := range rl.ScopeLogs {
+		scopeBase := base + proto.Size(&logspb.ScopeLogs{Scope: sl.Scope, SchemaUrl: sl.SchemaUrl})
+
+		size := scopeBase
+		var records []*logspb.LogRecord
+
+		for _, rec := range sl.LogRecords {
+			recSize := Size(rec)
+			if len(records) > 0 && size+recSize > maxBytes {
+				out = append(out, withRecords(rl, sl, records))
+				size, records = scopeBase, nil
+			}
+
+			size += recSize
+			records = append(records, rec)
+		}
+
+		if len(records) > 0 {
+			out = append(out, withRecords(rl, sl, records))
+		}
+	}
+
+	return out
+}
+
+// MaxRecordSize returns encoded size of export request holding only the largest record of rl,
+// Split never produces smaller chunk for it
+func MaxRecordSize(rl *logspb.ResourceLogs) int {
+	if rl == nil {
+		return 0
+	}
+
+	base := proto.Size(&logspb.ResourceLogs{Resource: rl.Resource, SchemaUrl: rl.SchemaUrl}) + wrapOverhead
+
+	largest := 0
+	for _, sl := range rl.ScopeLogs {
+		scopeBase := base + proto.Size(&logspb.ScopeLogs{Scope: sl.Scope, SchemaUrl: sl.SchemaUrl})
+
+		for _, rec := range sl.LogRecords {
+			largest = max(largest, scopeBase+Size(rec))
+		}
+	}
+
+	return largest
+}
+
+// Halve splits records of rl into two parts with the same resource and scopes.
+// ok is false when rl holds less than two records and can't be divided anymore.
+func Halve(rl *logspb.ResourceLogs) (left, right *logspb.ResourceLogs, ok bool) {
+	if rl == nil {
+		return nil, nil, false
+	}
+
+	total := 0
+	for _, sl := range rl.ScopeLogs {
+		total += len(sl.LogRecords)
+	}
+
+	if total < 2 {
+		return nil, nil, false
+	}
+
+	left = &logspb.ResourceLogs{Resource: rl.Resource, SchemaUrl: rl.SchemaUrl}
+	right = &logspb.ResourceLogs{Resource: rl.Resource, SchemaUrl: rl.SchemaUrl}
+
+	half := total / 2
+	for _, sl := range rl.ScopeLogs {
+		n := len(sl.LogRecords)
+
+		switch {
+		case half <= 0:
+			right.ScopeLogs = append(right.ScopeLogs, sl)
+		case half >= n:
+			left.ScopeLogs = append(left.ScopeLogs, sl)
+		default:
+			left.ScopeLogs = append(left.ScopeLogs, scopeWithRecords(sl, sl.LogRecords[:half]))
+			right.ScopeLogs = append(right.ScopeLogs, scopeWithRecords(sl, sl.LogRecords[half:]))
+		}
+
+		half -= n
+	}
+
+	return left, right, true
+}
+
+// Merge joins scopes of chunks produced by Split or Halve of the same resource logs
+func Merge(rls ...*logspb.ResourceLogs) *logspb.ResourceLogs {
+	if len(rls) == 0 {
+		return nil
+	}
+
+	out := &logspb.ResourceLogs{Resource: rls[0].Resource, SchemaUrl: rls[0].SchemaUrl}
+	for _, rl := range rls {
+		out.ScopeLogs = append(out.ScopeLogs, rl.ScopeLogs...)
+	}
+
+	return out
+}
+
+func withRecords(rl *logspb.ResourceLogs, sl *logspb.ScopeLogs, records []*logspb.LogRecord) *logspb.ResourceLogs {
+	return &logspb.ResourceLogs{
+		Resource:  rl.Resource,
+		SchemaUrl: rl.SchemaUrl,
+		ScopeLogs: []*logspb.ScopeLogs{scopeWithRecords(sl, records)},
+	}
+}
+
+func scopeWithRecords(sl *logspb.ScopeLogs, records []*logspb.LogRecord) *logspb.ScopeLogs {
+	return &logspb.ScopeLogs{
+		Scope:      sl.Scope,
+		SchemaUrl:  sl.SchemaUrl,
+		LogRecords: records,
+	}
+}
@@ -0,0 +1,126 @@
+package logtransform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func testResourceLogs(scopes, records, bodySize int) *logspb.ResourceLogs {
+	rl := &logspb.ResourceLogs{
+		Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{{
+			Key:   "service.name",
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "test"}},
+		}}},
+	}
+
+	for i := 0; i < scopes; i++ {
+		sl := &logspb.ScopeLogs{Scope: &commonpb.InstrumentationScope{Name: "scope"}}
+
+		for j := 0; j < records; j++ {
+			sl.LogRecords = append(sl.LogRecords, &logspb.LogRecord{
+				Body: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{
+					StringValue: strings.Repeat("x", bodySize),
+				}},
+			})
+		}
+
+		rl.ScopeLogs = append(rl.ScopeLogs, sl)
+	}
+
+	return rl
+}
+
+func countRecords(rls ...*logspb.ResourceLogs) int {
+	n := 0
+	for _, rl := range rls {
+		for _, sl := range rl.ScopeLogs {
+			n += len(sl.LogRecords)
+		}
+	}
+
+	return n
+}
+
+func requestSize(rl *logspb.ResourceLogs) int {
+	return proto.Size(&collogspb.ExportLogsServiceRequest{ResourceLogs: []*logspb.ResourceLogs{rl}})
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		rl       *logspb.ResourceLogs
+		maxBytes int
+		chunks   int
+	}{
+		{"disabled", testResourceLogs(2, 100, 100), 0, 1},
+		{"fits", testResourceLogs(2, 10, 100), 1 << 20, 1},
+		{"split", testResourceLogs(2, 100, 100), 1024, 24},
+		{"oversize record", testResourceLogs(1, 3, 2048), 1024, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chunks := Split(test.rl, test.maxBytes)
+			require.Len(t, chunks, test.chunks)
+			assert.Equal(t, countRecords(test.rl), countRecords(chunks...))
+
+			for _, chunk := range chunks {
+				assert.Equal(t, test.rl.Resource, chunk.Resource)
+
+				if test.maxBytes > 0 && countRecords(chunk) > 1 {
+					assert.LessOrEqual(t, requestSize(chunk), test.maxBytes)
+				}
+			}
+		})
+	}
+}
+
+func TestHalve(t *testing.T) {
+	rl := testResourceLogs(3, 3, 10)
+
+	left, right, ok := Halve(rl)
+	require.True(t, ok)
+	assert.Equal(t, 4, countRecords(left))
+	assert.Equal(t, 5, countRecords(right))
+	assert.Len(t, left.ScopeLogs, 2)
+	assert.Len(t, right.ScopeLogs, 2)
+
+	_, _, ok = Halve(testResourceLogs(1, 1, 10))
+	assert.False(t, ok)
+}
+
+func TestMaxRecordSize(t *testing.T) {
+	rl := testResourceLogs(1, 3, 10)
+	rl.ScopeLogs[0].LogRecords[2].Body = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{
+		StringValue: strings.Repeat("x", 1024),
+	}}
+
+	size := MaxRecordSize(rl)
+	assert.GreaterOrEqual(t, size, requestSize(withRecords(rl, rl.ScopeLogs[0], rl.ScopeLogs[0].LogRecords[2:])))
+
+	// small records share chunk, the largest one fits limit alone
+	chunks := Split(rl, size)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, 3, countRecords(chunks...))
+
+	assert.Zero(t, MaxRecordSize(nil))
+}
+
+func TestMerge(t *testing.T) {
+	rl := testResourceLogs(2, 10, 100)
+
+	merged := Merge(Split(rl, 1024)...)
+	assert.Equal(t, rl.Resource, merged.Resource)
+	assert.Equal(t, countRecords(rl), countRecords(merged))
+
+	assert.Nil(t, Merge())
+}
@@ -0,0 +1,254 @@
+// Package metricview configures aggregation, attributes and names of metric instruments declaratively
+package metricview
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tel-io/tel/v2/pkg/glob"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+var ErrInvalidView = errors.New("invalid metric view")
+
+// Settings of views
+const (
+	SettingBuckets     = "buckets"
+	SettingExponential = "exponential"
+	SettingAttributes  = "attributes"
+	SettingRename      = "rename"
+	SettingDrop        = "drop"
+)
+
+// Defaults of base2 exponential histogram, same as otel sdk ones
+const (
+	DefaultMaxSize  = 160
+	DefaultMaxScale = 20
+)
+
+// View changes stream of instruments matching Instrument, zero fields keep instrument defaults.
+// Buckets and Exponential apply to histograms only, other instruments keep their aggregation
+type View struct {
+	// Instrument is glob of instrument name where * matches any sequence and ? any char
+	Instrument string
+	// Buckets are boundaries of explicit bucket histogram
+	Buckets []float64
+	// Exponential switches histogram to base2 exponential one, MaxSize and MaxScale are optional,
+	// nil MaxScale keeps DefaultMaxScale as zero is valid scale
+	Exponential bool
+	MaxSize     int32
+	MaxScale    *int32
+	// Attributes is allow-list of attribute keys, nil keeps all attributes
+	Attributes []string
+	// Rename is new name of stream, Instrument must match single name as streams of the same name conflict
+	Rename string
+	// Drop drops measurements of instrument
+	Drop bool
+}
+
+func (v View) stream(i metric.Instrument) metric.Stream {
+	s := metric.Stream{
+		Name:        i.Name,
+		Description: i.Description,
+		Unit:        i.Unit,
+	}
+
+	if v.Rename != "" {
+		s.Name = v.Rename
+	}
+
+	if v.Attributes != nil {
+		keys := make([]attribute.Key, 0, len(v.Attributes))
+		for _, key := range v.Attributes {
+			keys = append(keys, attribute.Key(key))
+		}
+
+		s.AttributeFilter = attribute.NewAllowKeysFilter(keys...)
+	}
+
+	switch {
+	case v.Drop:
+		s.Aggregation = metric.AggregationDrop{}
+	case i.Kind != metric.InstrumentKindHistogram:
+	case v.Exponential:
+		maxScale := int32(DefaultMaxScale)
+		if v.MaxScale != nil {
+			maxScale = *v.MaxScale
+		}
+
+		s.Aggregation = metric.AggregationBase2ExponentialHistogram{
+			MaxSize:  nonZero(v.MaxSize, DefaultMaxSize),
+			MaxScale: maxScale,
+		}
+	case v.Buckets != nil:
+		s.Aggregation = metric.AggregationExplicitBucketHistogram{Boundaries: v.Buckets}
+	}
+
+	return s
+}
+
+func nonZero(v, def int32) int32 {
+	if v == 0 {
+		return def
+	}
+
+	return v
+}
+
+// New returns sdk view applying the first of views matching instrument,
+// instruments matching none of them keep default stream
+func New(views ...View) metric.View {
+	return func(i metric.Instrument) (metric.Stream, bool) {
+		for _, v := range views {
+			if glob.Match(v.Instrument, i.Name) {
+				return v.stream(i), true
+			}
+		}
+
+		return metric.Stream{}, false
+	}
+}
+
+// Parse parses views separated by ; or new line, lines starting with # are comments.
+// View is <instrument glob>=><setting>,<setting> where setting is buckets:<n>|<n>, exponential[:<max size>[:<max scale>]],
+// attributes:<key>|<key>, rename:<name> or drop:
+//
+//	http.server.duration=>buckets:0.01|0.1|1|10,attributes:http.route|http.method; rpc.*=>exponential; noisy.*=>drop
+func Parse(s string) ([]View, error) {
+	var views []View
+
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		for _, raw := range strings.Split(line, ";") {
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+
+			v, err := parseView(raw)
+			if err != nil {
+				return nil, fmt.Errorf("view %q: %w", raw, err)
+			}
+
+			views = append(views, v)
+		}
+	}
+
+	return views, nil
+}
+
+func parseView(raw string) (View, error) {
+	instrument, settings, ok := strings.Cut(raw, "=>")
+	if !ok {
+		return View{}, fmt.Errorf("%w: missing =>", ErrInvalidView)
+	}
+
+	v := View{Instrument: strings.TrimSpace(instrument)}
+	if v.Instrument == "" {
+		return v, fmt.Errorf("%w: empty instrument", ErrInvalidView)
+	}
+
+	for _, setting := range strings.Split(settings, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(setting), ":")
+
+		var err error
+
+		switch name {
+		case SettingBuckets:
+			v.Buckets, err = parseBuckets(arg)
+		case SettingExponential:
+			v.Exponential = true
+			v.MaxSize, v.MaxScale, err = parseExponential(arg)
+		case SettingAttributes:
+			v.Attributes = parseKeys(arg)
+		case SettingRename:
+			if v.Rename = strings.TrimSpace(arg); v.Rename == "" {
+				err = fmt.Errorf("%w: empty rename", ErrInvalidView)
+			}
+		case SettingDrop:
+			v.Drop = true
+		default:
+			err = fmt.Errorf("%w: unknown setting %q", ErrInvalidView, name)
+		}
+
+		if err != nil {
+			return v, err
+		}
+	}
+
+	if v.Exponential && v.Buckets != nil {
+		return v, fmt.Errorf("%w: buckets and exponential are exclusive", ErrInvalidView)
+	}
+
+	// the same check as sdk view has
+	if v.Rename != "" && strings.ContainsAny(v.Instrument, "*?") {
+		return v, fmt.Errorf("%w: rename of wildcard instrument", ErrInvalidView)
+	}
+
+	return v, nil
+}
+
+func parseBuckets(s string) ([]float64, error) {
+	keys := parseKeys(s)
+	buckets := make([]float64, 0, len(keys))
+
+	for _, key := range keys {
+		b, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bucket %q", ErrInvalidView, key)
+		}
+
+		if len(buckets) > 0 && b <= buckets[len(buckets)-1] {
+			return nil, fmt.Errorf("%w: buckets aren't increasing", ErrInvalidView)
+		}
+
+		buckets = append(buckets, b)
+	}
+
+	return buckets, nil
+}
+
+func parseExponential(s string) (maxSize int32, maxScale *int32, err error) {
+	if s == "" {
+		return 0, nil, nil
+	}
+
+	size, scale, _ := strings.Cut(s, ":")
+
+	v, err := strconv.ParseInt(size, 10, 32)
+	if err != nil || v <= 0 {
+		return 0, nil, fmt.Errorf("%w: exponential max size %q", ErrInvalidView, size)
+	}
+
+	maxSize = int32(v)
+
+	if scale != "" {
+		v, err = strconv.ParseInt(scale, 10, 32)
+		if err != nil || v < -10 || v > 20 {
+			return 0, nil, fmt.Errorf("%w: exponential max scale %q", ErrInvalidView, scale)
+		}
+
+		s := int32(v)
+		maxScale = &s
+	}
+
+	return maxSize, maxScale, nil
+}
+
+func parseKeys(s string) []string {
+	keys := []string{}
+
+	for _, key := range strings.Split(s, "|") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
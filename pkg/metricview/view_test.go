@@ -0,0 +1,123 @@
+package metricview
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestParse(t *testing.T) {
+	views, err := Parse(`
+# latency of routes
+http.server.duration=>buckets:0.1|1|10, attributes:http.route
+rpc.*=>exponential:80:10; db.*=>exponential; queue.*=>exponential:80:0
+noisy.*=>drop; old.name=>rename:new.name
+`)
+	require.NoError(t, err)
+
+	scale10, scale0 := int32(10), int32(0)
+	assert.Equal(t, []View{
+		{Instrument: "http.server.duration", Buckets: []float64{0.1, 1, 10}, Attributes: []string{"http.route"}},
+		{Instrument: "rpc.*", Exponential: true, MaxSize: 80, MaxScale: &scale10},
+		{Instrument: "db.*", Exponential: true},
+		{Instrument: "queue.*", Exponential: true, MaxSize: 80, MaxScale: &scale0},
+		{Instrument: "noisy.*", Drop: true},
+		{Instrument: "old.name", Rename: "new.name"},
+	}, views)
+
+	for _, s := range []string{
+		"http.*",
+		"=>drop",
+		"http.*=>buckets:1|a",
+		"http.*=>buckets:10|1",
+		"http.*=>exponential:0",
+		"http.*=>exponential:160:30",
+		"http.*=>buckets:1,exponential",
+		"http.*=>rename:",
+		"rpc.*=>rename:rpc",
+		"rpc.call?=>rename:rpc",
+		"http.*=>sum",
+	} {
+		_, err := Parse(s)
+		assert.ErrorIs(t, err, ErrInvalidView, s)
+	}
+}
+
+func TestNew(t *testing.T) {
+	scale0 := int32(0)
+
+	reader := metric.NewManualReader()
+	mp := metric.NewMeterProvider(metric.WithReader(reader), metric.WithView(New(
+		View{Instrument: "http.server.duration", Buckets: []float64{1, 10}, Attributes: []string{"route"}},
+		View{Instrument: "http.*", Exponential: true},
+		View{Instrument: "noisy.*", Drop: true},
+		View{Instrument: "old.requests", Rename: "requests"},
+		View{Instrument: "queue.wait", Exponential: true, MaxScale: &scale0},
+	)))
+
+	ctx := context.Background()
+	meter := mp.Meter("test")
+	attrs := otelmetric.WithAttributes(attribute.String("route", "/"), attribute.String("user_id", "1"))
+
+	duration, err := meter.Float64Histogram("http.server.duration")
+	require.NoError(t, err)
+	duration.Record(ctx, 5, attrs)
+
+	size, err := meter.Int64Histogram("http.server.size")
+	require.NoError(t, err)
+	size.Record(ctx, 5, attrs)
+
+	// aggregation applies to histograms only
+	active, err := meter.Int64UpDownCounter("http.server.active")
+	require.NoError(t, err)
+	active.Add(ctx, 1, attrs)
+
+	noisy, err := meter.Int64Counter("noisy.counter")
+	require.NoError(t, err)
+	noisy.Add(ctx, 1)
+
+	requests, err := meter.Int64Counter("old.requests")
+	require.NoError(t, err)
+	requests.Add(ctx, 1)
+
+	wait, err := meter.Float64Histogram("queue.wait")
+	require.NoError(t, err)
+	wait.Record(ctx, 5)
+
+	other, err := meter.Int64Counter("other")
+	require.NoError(t, err)
+	other.Add(ctx, 1)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	assert.Len(t, metrics, 6)
+
+	hist := metrics["http.server.duration"].(metricdata.Histogram[float64]) //nolint:forcetypeassert
+	require.Len(t, hist.DataPoints, 1)
+	assert.Equal(t, []float64{1, 10}, hist.DataPoints[0].Bounds)
+	assert.Equal(t, attribute.NewSet(attribute.String("route", "/")), hist.DataPoints[0].Attributes)
+
+	assert.IsType(t, metricdata.ExponentialHistogram[int64]{}, metrics["http.server.size"])
+	assert.IsType(t, metricdata.Sum[int64]{}, metrics["http.server.active"])
+	// explicit zero scale isn't replaced by default one
+	exp := metrics["queue.wait"].(metricdata.ExponentialHistogram[float64]) //nolint:forcetypeassert
+	require.Len(t, exp.DataPoints, 1)
+	assert.Zero(t, exp.DataPoints[0].Scale)
+
+	assert.Contains(t, metrics, "requests")
+	assert.Contains(t, metrics, "other")
+	assert.NotContains(t, metrics, "noisy.counter")
+}
@@ -0,0 +1,162 @@
+// Package resourcedetector contains resource detectors of kubernetes pod and container
+// which are not covered by otel sdk or require to be configured for tests
+package resourcedetector
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+// Defaults of detectors
+const (
+	// PodInfoDir is mount path of downward API volume
+	PodInfoDir = "/etc/podinfo"
+	// ServiceAccountDir keeps namespace of pod, mounted by kubernetes
+	ServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// CgroupPath lists cgroups of current process
+	CgroupPath = "/proc/self/cgroup"
+
+	envKubernetesHost = "KUBERNETES_SERVICE_HOST"
+)
+
+var (
+	_ resource.Detector = K8s{}
+	_ resource.Detector = Container{}
+)
+
+// field of k8s resource: env names in priority order and file name in downward API volume
+type field struct {
+	key  attribute.Key
+	envs []string
+	file string
+}
+
+//nolint:gochecknoglobals
+var k8sFields = []field{
+	{key: semconv.K8SPodNameKey, envs: []string{"K8S_POD_NAME", "POD_NAME"}, file: "pod_name"},
+	{key: semconv.K8SNamespaceNameKey, envs: []string{"K8S_NAMESPACE_NAME", "POD_NAMESPACE"}, file: "pod_namespace"},
+	{key: semconv.K8SNodeNameKey, envs: []string{"K8S_NODE_NAME", "NODE_NAME"}, file: "node_name"},
+	{key: semconv.K8SPodUIDKey, envs: []string{"K8S_POD_UID", "POD_UID"}, file: "pod_uid"},
+}
+
+// K8s detects pod name, namespace, node and uid from downward API env variables or files.
+// Inside of cluster pod name falls back to hostname and namespace to service account namespace
+type K8s struct {
+	// PodInfoDir is downward API volume with files pod_name, pod_namespace, node_name, pod_uid
+	PodInfoDir string
+	// ServiceAccountDir contains namespace file
+	ServiceAccountDir string
+}
+
+func NewK8s() K8s {
+	return K8s{PodInfoDir: PodInfoDir, ServiceAccountDir: ServiceAccountDir}
+}
+
+// Detect returns empty resource outside of kubernetes
+func (d K8s) Detect(_ context.Context) (*resource.Resource, error) {
+	values := make(map[attribute.Key]string, len(k8sFields))
+
+	for _, f := range k8sFields {
+		if v := d.lookup(f); v != "" {
+			values[f.key] = v
+		}
+	}
+
+	if _, ok := os.LookupEnv(envKubernetesHost); ok {
+		if _, ok = values[semconv.K8SPodNameKey]; !ok {
+			// hostname of pod is its name unless spec.hostname is set
+			if host, err := os.Hostname(); err == nil {
+				values[semconv.K8SPodNameKey] = host
+			}
+		}
+
+		if _, ok = values[semconv.K8SNamespaceNameKey]; !ok {
+			if v := readFile(d.ServiceAccountDir, "namespace"); v != "" {
+				values[semconv.K8SNamespaceNameKey] = v
+			}
+		}
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(values))
+
+	for _, f := range k8sFields {
+		if v, ok := values[f.key]; ok {
+			attrs = append(attrs, f.key.String(v))
+		}
+	}
+
+	return resource.NewSchemaless(attrs...), nil
+}
+
+func (d K8s) lookup(f field) string {
+	for _, env := range f.envs {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+
+	return readFile(d.PodInfoDir, f.file)
+}
+
+func readFile(dir, name string) string {
+	if dir == "" {
+		return ""
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(b))
+}
+
+// container id is 64 hex chars, runtimes add prefix and suffix: docker-<id>.scope, cri-containerd-<id>.scope
+var containerIDRe = regexp.MustCompile(`(?:^|[/\-:])([0-9a-f]{64})(?:\.scope)?$`)
+
+// Container detects container id from cgroup of process
+type Container struct {
+	// CgroupPath is cgroup file of process
+	CgroupPath string
+}
+
+func NewContainer() Container {
+	return Container{CgroupPath: CgroupPath}
+}
+
+// Detect returns empty resource if process is not in container
+func (d Container) Detect(_ context.Context) (*resource.Resource, error) {
+	f, err := os.Open(d.CgroupPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return resource.Empty(), nil
+		}
+
+		return nil, errors.WithMessage(err, "open cgroup")
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		if m := containerIDRe.FindStringSubmatch(strings.TrimSpace(parts[2])); m != nil {
+			return resource.NewSchemaless(semconv.ContainerIDKey.String(m[1])), nil
+		}
+	}
+
+	return resource.Empty(), errors.WithMessage(scanner.Err(), "read cgroup")
+}
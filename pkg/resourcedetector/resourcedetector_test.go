@@ -0,0 +1,105 @@
+package resourcedetector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+const containerID = "3c3f2d0e6c1f1a5a9e27f6f1f5a2b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2"
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func TestK8s(t *testing.T) {
+	t.Setenv(envKubernetesHost, "")
+	_ = os.Unsetenv(envKubernetesHost)
+
+	t.Run("outside of cluster", func(t *testing.T) {
+		res, err := K8s{}.Detect(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.Len())
+	})
+
+	t.Run("env and files", func(t *testing.T) {
+		t.Setenv("POD_NAME", "api-7d9f-x2x")
+		t.Setenv("K8S_NODE_NAME", "node-1")
+
+		dir := writeFiles(t, map[string]string{"pod_namespace": "payments\n", "pod_name": "overridden-by-env"})
+
+		res, err := K8s{PodInfoDir: dir}.Detect(context.Background())
+		require.NoError(t, err)
+
+		set := res.Set()
+		assertValue(t, set, semconv.K8SPodNameKey, "api-7d9f-x2x")
+		assertValue(t, set, semconv.K8SNamespaceNameKey, "payments")
+		assertValue(t, set, semconv.K8SNodeNameKey, "node-1")
+
+		_, ok := set.Value(semconv.K8SPodUIDKey)
+		assert.False(t, ok)
+	})
+
+	t.Run("in cluster fallbacks", func(t *testing.T) {
+		t.Setenv(envKubernetesHost, "10.0.0.1")
+
+		dir := writeFiles(t, map[string]string{"namespace": "billing"})
+
+		res, err := K8s{ServiceAccountDir: dir}.Detect(context.Background())
+		require.NoError(t, err)
+
+		host, err := os.Hostname()
+		require.NoError(t, err)
+
+		assertValue(t, res.Set(), semconv.K8SPodNameKey, host)
+		assertValue(t, res.Set(), semconv.K8SNamespaceNameKey, "billing")
+	})
+}
+
+func TestContainer(t *testing.T) {
+	for name, cgroup := range map[string]string{
+		"docker":     "12:memory:/docker/" + containerID + "\n",
+		"systemd":    "0::/system.slice/docker-" + containerID + ".scope\n",
+		"containerd": "1:name=systemd:/kubepods/burstable/pod1/cri-containerd-" + containerID + ".scope\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{"cgroup": "13:pids:/\n" + cgroup})
+
+			res, err := Container{CgroupPath: filepath.Join(dir, "cgroup")}.Detect(context.Background())
+			require.NoError(t, err)
+			assertValue(t, res.Set(), semconv.ContainerIDKey, containerID)
+		})
+	}
+
+	t.Run("not in container", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{"cgroup": "0::/user.slice/user-1000.slice/session-2.scope\n"})
+
+		res, err := Container{CgroupPath: filepath.Join(dir, "cgroup")}.Detect(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.Len())
+
+		res, err = Container{CgroupPath: filepath.Join(dir, "missing")}.Detect(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.Len())
+	})
+}
+
+func assertValue(t *testing.T, set *attribute.Set, key attribute.Key, expected string) {
+	t.Helper()
+
+	v, ok := set.Value(key)
+	require.True(t, ok, key)
+	assert.Equal(t, expected, v.AsString())
+}
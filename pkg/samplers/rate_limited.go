@@ -0,0 +1,205 @@
+package samplers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// ProbabilityAttributeKey keeps effective sampling probability of sampled span,
+// span metrics are reweighted by 1/probability
+var ProbabilityAttributeKey = attribute.Key("sampling.probability")
+
+// maxNames limits span names with own budget share, the rest use common budget only
+const maxNames = 1000
+
+// RateLimitedOption configures RateLimited sampler
+type RateLimitedOption func(*rateLimitedSampler)
+
+// WithMinNameShare reserves share of budget for every span name,
+// so rare span names are sampled even when budget is exhausted by frequent ones.
+// Reserved samples are taken over the limit, so total rate is up to perSecond*(1+1000*share)
+// for the first 1000 span names
+func WithMinNameShare(share float64) RateLimitedOption {
+	return func(s *rateLimitedSampler) {
+		s.share = share
+	}
+}
+
+// RateLimited samples up to perSecond traces per second, burst is one second of budget.
+// Spans with error attribute or link attribute are always sampled like StatusTraceIDRatioBased does.
+// Sampled spans carry ProbabilityAttributeKey with sampled/seen ratio of the previous second
+func RateLimited(perSecond float64, opts ...RateLimitedOption) trace.Sampler {
+	return newRateLimited(perSecond, time.Now, opts...)
+}
+
+func newRateLimited(perSecond float64, now func() time.Time, opts ...RateLimitedOption) *rateLimitedSampler {
+	s := &rateLimitedSampler{
+		rate:  perSecond,
+		now:   now,
+		names: make(map[string]*nameBudget),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.budget = newTokenBucket(s.rate, s.now())
+	s.window.probability = 1
+
+	s.description = fmt.Sprintf("RateLimited{%g}", s.rate)
+	if s.share > 0 {
+		s.description = fmt.Sprintf("RateLimited{%g,%g}", s.rate, s.share)
+	}
+
+	return s
+}
+
+type rateLimitedSampler struct {
+	mu sync.Mutex
+
+	rate   float64
+	share  float64
+	now    func() time.Time
+	budget *tokenBucket
+	window window
+	names  map[string]*nameBudget
+
+	description string
+}
+
+type nameBudget struct {
+	budget *tokenBucket
+	window window
+}
+
+func (s *rateLimitedSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	res := trace.SamplingResult{
+		Decision:   trace.Drop,
+		Tracestate: oteltrace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+
+	if hasErrorAttribute(p) {
+		res.Decision = trace.RecordAndSample
+		res.Attributes = []attribute.KeyValue{ProbabilityAttributeKey.Float64(1)}
+
+		return res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	w := &s.window
+
+	var sampled bool
+
+	if nb := s.name(p.Name, now); nb != nil {
+		sampled = nb.budget.take(now)
+		w = &nb.window
+	}
+
+	// common budget is charged for reserved samples as well, so they aren't sampled twice,
+	// reserved samples are kept when it's exhausted, total rate may exceed the limit, see WithMinNameShare
+	if s.budget.take(now) {
+		sampled = true
+	}
+
+	probability := w.observe(now, sampled)
+
+	if sampled {
+		res.Decision = trace.RecordAndSample
+		res.Attributes = []attribute.KeyValue{ProbabilityAttributeKey.Float64(probability)}
+	}
+
+	return res
+}
+
+func (s *rateLimitedSampler) name(name string, now time.Time) *nameBudget {
+	if s.share <= 0 {
+		return nil
+	}
+
+	if nb, ok := s.names[name]; ok {
+		return nb
+	}
+
+	if len(s.names) >= maxNames {
+		return nil
+	}
+
+	nb := &nameBudget{budget: newTokenBucket(s.rate*s.share, now), window: window{probability: 1}}
+	s.names[name] = nb
+
+	return nb
+}
+
+func (s *rateLimitedSampler) Description() string {
+	return s.description
+}
+
+// window counts decisions per second, probability is sampled/seen of the previous second
+type window struct {
+	start         time.Time
+	seen, sampled int
+	probability   float64
+}
+
+func (w *window) observe(now time.Time, sampled bool) float64 {
+	if elapsed := now.Sub(w.start); elapsed >= time.Second {
+		switch {
+		case elapsed >= 2*time.Second || w.seen == 0:
+			// idle, no estimate
+			w.probability = 1
+		default:
+			w.probability = float64(w.sampled) / float64(w.seen)
+		}
+
+		w.start, w.seen, w.sampled = now, 0, 0
+	}
+
+	w.seen++
+	if sampled {
+		w.sampled++
+	}
+
+	return w.probability
+}
+
+// tokenBucket refills rate tokens per second up to rate, at least 1, it's not safe for concurrent use
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, now time.Time) *tokenBucket {
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
@@ -0,0 +1,120 @@
+package samplers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func sample(s trace.Sampler, name string, attrs ...attribute.KeyValue) trace.SamplingResult {
+	return s.ShouldSample(trace.SamplingParameters{ParentContext: context.Background(), Name: name, Attributes: attrs})
+}
+
+func probability(t *testing.T, res trace.SamplingResult) float64 {
+	t.Helper()
+
+	for _, kv := range res.Attributes {
+		if kv.Key == ProbabilityAttributeKey {
+			return kv.Value.AsFloat64()
+		}
+	}
+
+	t.Fatalf("no %s attribute", ProbabilityAttributeKey)
+
+	return 0
+}
+
+func TestRateLimited(t *testing.T) {
+	c := &clock{now: time.Unix(0, 0)}
+	s := newRateLimited(2, c.Now)
+
+	assert.Equal(t, "RateLimited{2}", s.Description())
+
+	assert.Equal(t, trace.RecordAndSample, sample(s, "a").Decision)
+	assert.Equal(t, trace.RecordAndSample, sample(s, "a").Decision)
+
+	for i := 0; i < 6; i++ {
+		assert.Equal(t, trace.Drop, sample(s, "a").Decision)
+	}
+
+	t.Run("error spans are kept", func(t *testing.T) {
+		res := sample(s, "a", errorAttributeKey.Bool(true))
+		assert.Equal(t, trace.RecordAndSample, res.Decision)
+		assert.Equal(t, 1.0, probability(t, res))
+	})
+
+	t.Run("probability of previous second", func(t *testing.T) {
+		c.Add(time.Second)
+
+		res := sample(s, "a")
+		assert.Equal(t, trace.RecordAndSample, res.Decision)
+		assert.Equal(t, 0.25, probability(t, res))
+
+		c.Add(time.Hour)
+		assert.Equal(t, 1.0, probability(t, sample(s, "a")))
+	})
+}
+
+func TestRateLimited_MinNameShare(t *testing.T) {
+	c := &clock{now: time.Unix(0, 0)}
+	s := newRateLimited(10, c.Now, WithMinNameShare(0.1))
+
+	assert.Equal(t, "RateLimited{10,0.1}", s.Description())
+
+	sampled := map[string]int{}
+
+	for i := 0; i < 10; i++ {
+		// frequent name exhausts common budget first
+		for j := 0; j < 100; j++ {
+			if sample(s, "GET /orders").Decision == trace.RecordAndSample {
+				sampled["GET /orders"]++
+			}
+		}
+
+		if sample(s, "GET /rare").Decision == trace.RecordAndSample {
+			sampled["GET /rare"]++
+		}
+
+		c.Add(time.Second)
+	}
+
+	assert.Equal(t, 10, sampled["GET /rare"])
+	assert.InDelta(t, 100, sampled["GET /orders"], 10)
+
+	res := sample(s, "GET /orders")
+	assert.InDelta(t, 0.11, probability(t, res), 0.01)
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newTokenBucket(2, now)
+
+	assert.True(t, b.take(now))
+	assert.True(t, b.take(now))
+	assert.False(t, b.take(now))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, b.take(now))
+	assert.False(t, b.take(now))
+
+	now = now.Add(time.Hour)
+	assert.True(t, b.take(now))
+	assert.True(t, b.take(now))
+	assert.False(t, b.take(now))
+}
@@ -0,0 +1,253 @@
+package samplers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tel-io/tel/v2/pkg/glob"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var ErrInvalidRule = errors.New("invalid sampler rule")
+
+// Actions of sampler rules
+const (
+	ActionDrop      = "drop"
+	ActionAlways    = "always"
+	ActionRatio     = "ratio"
+	ActionRateLimit = "ratelimit"
+)
+
+// Reserved keys of rule conditions, other keys match span start attributes
+const (
+	KeyName  = "name"
+	KeyKind  = "kind"
+	KeyScope = "scope"
+)
+
+type scopeKey struct{}
+
+// ContextWithScope keeps instrumentation scope name for rules, sampling parameters have no scope
+func ContextWithScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+func scopeFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	s, _ := ctx.Value(scopeKey{}).(string)
+
+	return s
+}
+
+// Rule picks sampler of span, empty fields match any span.
+// Name, Scope and Attributes values are globs where * matches any sequence and ? any char
+type Rule struct {
+	Name string
+	// Kind is ignored if unspecified
+	Kind  oteltrace.SpanKind
+	Scope string
+	// Attributes are matched against string form of span start attributes
+	Attributes map[attribute.Key]string
+
+	Sampler trace.Sampler
+}
+
+func (r Rule) match(p trace.SamplingParameters) bool {
+	if r.Name != "" && !glob.Match(r.Name, p.Name) {
+		return false
+	}
+
+	if r.Kind != oteltrace.SpanKindUnspecified && r.Kind != p.Kind {
+		return false
+	}
+
+	if r.Scope != "" && !glob.Match(r.Scope, scopeFromContext(p.ParentContext)) {
+		return false
+	}
+
+	for key, pattern := range r.Attributes {
+		if !matchAttribute(p.Attributes, key, pattern) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchAttribute(attrs []attribute.KeyValue, key attribute.Key, pattern string) bool {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return glob.Match(pattern, kv.Value.Emit())
+		}
+	}
+
+	return false
+}
+
+// RuleBased applies sampler of the first matching rule, fallback is used if no rule matches.
+// Wrap it with trace.ParentBased to follow upstream decisions
+func RuleBased(fallback trace.Sampler, rules ...Rule) trace.Sampler {
+	desc := make([]string, 0, len(rules)+1)
+	for _, r := range rules {
+		desc = append(desc, r.Sampler.Description())
+	}
+
+	desc = append(desc, fallback.Description())
+
+	return ruleBasedSampler{
+		rules:       rules,
+		fallback:    fallback,
+		description: fmt.Sprintf("RuleBased{%s}", strings.Join(desc, ",")),
+	}
+}
+
+type ruleBasedSampler struct {
+	rules       []Rule
+	fallback    trace.Sampler
+	description string
+}
+
+func (s ruleBasedSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	for _, r := range s.rules {
+		if r.match(p) {
+			return r.Sampler.ShouldSample(p)
+		}
+	}
+
+	return s.fallback.ShouldSample(p)
+}
+
+func (s ruleBasedSampler) Description() string {
+	return s.description
+}
+
+// ParseRules parses rules separated by ";" in form: [key=glob[,key=glob...]]=>action
+//
+// Keys are name, kind (server, client, internal, producer, consumer), scope or attribute key.
+// Actions are drop, always, ratio:<fraction in [0, 1]> or ratelimit:<positive traces per second>, for instance:
+//
+//	http.route=/health=>drop; kind=server,http.route=/orders*=>ratio:0.5; scope=pgx=>ratelimit:10
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, raw := range strings.Split(s, ";") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		r, err := parseRule(raw)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "rule %q", raw)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+func parseRule(raw string) (Rule, error) {
+	var r Rule
+
+	conds, action, ok := strings.Cut(raw, "=>")
+	if !ok {
+		return r, errors.WithMessage(ErrInvalidRule, "missing =>")
+	}
+
+	sampler, err := parseAction(strings.TrimSpace(action))
+	if err != nil {
+		return r, err
+	}
+
+	r.Sampler = sampler
+
+	for _, cond := range strings.Split(conds, ",") {
+		cond = strings.TrimSpace(cond)
+		if cond == "" || cond == "*" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(cond, "=")
+		if !ok {
+			return r, errors.WithMessagef(ErrInvalidRule, "condition %q is not key=value", cond)
+		}
+
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case KeyName:
+			r.Name = value
+		case KeyScope:
+			r.Scope = value
+		case KeyKind:
+			if r.Kind = spanKind(value); r.Kind == oteltrace.SpanKindUnspecified {
+				return r, errors.WithMessagef(ErrInvalidRule, "unknown span kind %q", value)
+			}
+		default:
+			if r.Attributes == nil {
+				r.Attributes = make(map[attribute.Key]string)
+			}
+
+			r.Attributes[attribute.Key(key)] = value
+		}
+	}
+
+	return r, nil
+}
+
+func spanKind(s string) oteltrace.SpanKind {
+	for _, k := range []oteltrace.SpanKind{
+		oteltrace.SpanKindInternal,
+		oteltrace.SpanKindServer,
+		oteltrace.SpanKindClient,
+		oteltrace.SpanKindProducer,
+		oteltrace.SpanKindConsumer,
+	} {
+		if k.String() == s {
+			return k
+		}
+	}
+
+	return oteltrace.SpanKindUnspecified
+}
+
+func parseAction(action string) (trace.Sampler, error) {
+	name, arg, _ := strings.Cut(action, ":")
+
+	switch name {
+	case ActionDrop:
+		return trace.NeverSample(), nil
+	case ActionAlways:
+		return trace.AlwaysSample(), nil
+	case ActionRatio, ActionRateLimit:
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, errors.WithMessagef(ErrInvalidRule, "%s argument %q", name, arg)
+		}
+
+		if name == ActionRatio {
+			if v < 0 || v > 1 {
+				return nil, errors.WithMessagef(ErrInvalidRule, "%s argument %q out of [0, 1]", name, arg)
+			}
+
+			return trace.TraceIDRatioBased(v), nil
+		}
+
+		if v <= 0 {
+			return nil, errors.WithMessagef(ErrInvalidRule, "%s argument %q isn't positive", name, arg)
+		}
+
+		return RateLimited(v), nil
+	}
+
+	return nil, errors.WithMessagef(ErrInvalidRule, "unknown action %q", action)
+}
@@ -0,0 +1,95 @@
+package samplers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(" http.route=/health=>drop; kind=server,http.route=/orders*=>ratio:0.5;scope=pgx=>ratelimit:10;=>always ")
+	require.NoError(t, err)
+	require.Len(t, rules, 4)
+
+	assert.Equal(t, map[attribute.Key]string{"http.route": "/health"}, rules[0].Attributes)
+	assert.Equal(t, "AlwaysOffSampler", rules[0].Sampler.Description())
+
+	assert.Equal(t, oteltrace.SpanKindServer, rules[1].Kind)
+	assert.Equal(t, "TraceIDRatioBased{0.5}", rules[1].Sampler.Description())
+
+	assert.Equal(t, "pgx", rules[2].Scope)
+	assert.Equal(t, "RateLimited{10}", rules[2].Sampler.Description())
+
+	assert.Equal(t, Rule{Sampler: trace.AlwaysSample()}, rules[3])
+
+	for _, s := range []string{"name=x", "name=x=>sometimes", "kind=daemon=>drop", "name=>ratio:half", "route=>drop",
+		"name=x=>ratio:1.5", "name=x=>ratio:-0.1", "name=x=>ratelimit:0", "name=x=>ratelimit:-1"} {
+		_, err := ParseRules(s)
+		assert.ErrorIs(t, err, ErrInvalidRule, s)
+	}
+}
+
+func TestRuleBased(t *testing.T) {
+	rules, err := ParseRules("http.route=/health=>drop;http.route=/metrics=>drop;name=GET /debug/*,kind=server=>always;scope=pgx=>drop")
+	require.NoError(t, err)
+
+	s := RuleBased(trace.AlwaysSample(), rules...)
+
+	tests := []struct {
+		name   string
+		params trace.SamplingParameters
+		want   trace.SamplingDecision
+	}{
+		{
+			name: "health",
+			params: trace.SamplingParameters{Name: "GET /health", Kind: oteltrace.SpanKindServer,
+				Attributes: []attribute.KeyValue{attribute.String("http.route", "/health")}},
+			want: trace.Drop,
+		},
+		{
+			name: "metrics",
+			params: trace.SamplingParameters{Name: "GET /metrics",
+				Attributes: []attribute.KeyValue{attribute.String("http.route", "/metrics")}},
+			want: trace.Drop,
+		},
+		{
+			name:   "glob name and kind",
+			params: trace.SamplingParameters{Name: "GET /debug/pprof", Kind: oteltrace.SpanKindServer},
+			want:   trace.RecordAndSample,
+		},
+		{
+			name:   "scope",
+			params: trace.SamplingParameters{ParentContext: ContextWithScope(context.Background(), "pgx"), Name: "query"},
+			want:   trace.Drop,
+		},
+		{
+			name:   "fallback",
+			params: trace.SamplingParameters{ParentContext: context.Background(), Name: "GET /orders"},
+			want:   trace.RecordAndSample,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, s.ShouldSample(tt.params).Decision)
+		})
+	}
+
+	t.Run("parent based", func(t *testing.T) {
+		tp := trace.NewTracerProvider(trace.WithSampler(trace.ParentBased(s)))
+
+		ctx, parent := tp.Tracer("test").Start(context.Background(), "GET /orders")
+		defer parent.End()
+
+		_, child := tp.Tracer("test").Start(ctx, "GET /health",
+			oteltrace.WithAttributes(attribute.String("http.route", "/health")))
+		defer child.End()
+
+		assert.True(t, child.SpanContext().IsSampled())
+	})
+}
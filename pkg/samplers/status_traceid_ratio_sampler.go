@@ -24,27 +24,30 @@ type statusTraceIDRatioSampler struct {
 func (s statusTraceIDRatioSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
 	res := s.traceIDRatioSampler.ShouldSample(p)
 
+	if hasErrorAttribute(p) {
+		res.Decision = trace.RecordAndSample
+	}
+
+	return res
+}
+
+// hasErrorAttribute reports whether span or one of its links has error attribute
+func hasErrorAttribute(p trace.SamplingParameters) bool {
 	for _, attr := range p.Attributes {
-		if attr.Key != errorAttributeKey {
-			continue
+		if attr.Key == errorAttributeKey {
+			return true
 		}
-
-		res.Decision = trace.RecordAndSample
-		return res
 	}
 
 	for _, link := range p.Links {
 		for _, attr := range link.Attributes {
-			if attr.Key != errorAttributeKey {
-				continue
+			if attr.Key == errorAttributeKey {
+				return true
 			}
-
-			res.Decision = trace.RecordAndSample
-			return res
 		}
 	}
 
-	return res
+	return false
 }
 
 func (ts statusTraceIDRatioSampler) Description() string {
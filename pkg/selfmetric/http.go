@@ -0,0 +1,100 @@
+package selfmetric
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+type sizeKey struct{}
+
+// ContextWithSize passes size of export request before compression to RoundTripper
+func ContextWithSize(ctx context.Context, size int) context.Context {
+	return context.WithValue(ctx, sizeKey{}, size)
+}
+
+// RoundTripper records export requests of OTLP http client, failed requests are counted by http status code.
+// Size is taken from request context, see ContextWithSize, or from content length of uncompressed request
+func (m *Metrics) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+
+		size, ok := req.Context().Value(sizeKey{}).(int)
+		if !ok {
+			size = int(req.ContentLength)
+		}
+
+		switch {
+		case err != nil:
+			m.exported(req.Context(), time.Since(start), size, errorCode(err))
+		case resp.StatusCode >= http.StatusMultipleChoices:
+			m.exported(req.Context(), time.Since(start), size, strconv.Itoa(resp.StatusCode))
+		default:
+			m.exported(req.Context(), time.Since(start), size, "")
+		}
+
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TraceClient records uploads of OTLP trace client which has no access to its http client
+func TraceClient(client otlptrace.Client, mp metric.MeterProvider) otlptrace.Client {
+	return &traceClient{Client: client, metrics: New(mp, SignalTraces)}
+}
+
+type traceClient struct {
+	otlptrace.Client
+
+	metrics *Metrics
+}
+
+func (c *traceClient) UploadTraces(ctx context.Context, spans []*tracepb.ResourceSpans) error {
+	start := time.Now()
+	err := c.Client.UploadTraces(ctx, spans)
+
+	c.metrics.Exported(ctx, time.Since(start), proto.Size(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spans}), err)
+
+	return err
+}
+
+// MetricExporter records exports of OTLP metric exporter which has no access to its http client.
+// Request size isn't known outside of exporter, so sent bytes aren't counted
+func MetricExporter(exp sdkmetric.Exporter, mp metric.MeterProvider) sdkmetric.Exporter {
+	return &metricExporter{Exporter: exp, mp: mp}
+}
+
+type metricExporter struct {
+	sdkmetric.Exporter
+
+	// mp is Deferred provider usually, instruments are created on the first export
+	mp      metric.MeterProvider
+	once    sync.Once
+	metrics *Metrics
+}
+
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	start := time.Now()
+	err := e.Exporter.Export(ctx, rm)
+
+	e.once.Do(func() { e.metrics = New(e.mp, SignalMetrics) })
+	e.metrics.exported(ctx, time.Since(start), -1, errorCode(err))
+
+	return err
+}
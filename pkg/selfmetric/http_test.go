@@ -0,0 +1,79 @@
+package selfmetric
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetrics_RoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	reader := sdkmetric.NewManualReader()
+	client := &http.Client{
+		Transport: New(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), SignalLogs).
+			RoundTripper(http.DefaultTransport),
+	}
+
+	post := func(ctx context.Context, path string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+path, strings.NewReader("12345"))
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	post(context.Background(), "/")
+	post(ContextWithSize(context.Background(), 10), "/down")
+
+	data := collect(t, reader)
+
+	duration := data[MetricExportDuration].(metricdata.Histogram[float64]).DataPoints[0]
+	assert.Equal(t, uint64(2), duration.Count)
+
+	assert.Equal(t, int64(15), data[MetricSentBytes].(metricdata.Sum[int64]).DataPoints[0].Value)
+
+	exportErrors := data[MetricExportErrors].(metricdata.Sum[int64]).DataPoints[0]
+	assert.Equal(t, int64(1), exportErrors.Value)
+
+	code, _ := exportErrors.Attributes.Value(CodeKey)
+	assert.Equal(t, "503", code.AsString())
+}
+
+type failingExporter struct {
+	sdkmetric.Exporter
+}
+
+func (failingExporter) Export(context.Context, *metricdata.ResourceMetrics) error {
+	return errors.New("collector is down")
+}
+
+func TestMetricExporter(t *testing.T) {
+	mp := &Deferred{}
+	exp := MetricExporter(failingExporter{}, mp)
+
+	reader := sdkmetric.NewManualReader()
+	mp.Set(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	require.Error(t, exp.Export(context.Background(), &metricdata.ResourceMetrics{}))
+
+	data := collect(t, reader)
+
+	assert.Contains(t, data, MetricExportDuration)
+	assert.NotContains(t, data, MetricSentBytes)
+	assert.Equal(t, int64(1), data[MetricExportErrors].(metricdata.Sum[int64]).DataPoints[0].Value)
+}
@@ -0,0 +1,248 @@
+// Package selfmetric exposes metrics of telemetry pipeline itself:
+// queues, dropped items, exports to collector and reconnects.
+package selfmetric
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const instrumentationName = "github.com/tel-io/tel/v2/pkg/selfmetric"
+
+// Signals of telemetry pipeline
+const (
+	SignalLogs    = "logs"
+	SignalTraces  = "traces"
+	SignalMetrics = "metrics"
+)
+
+const (
+	// MetricQueueSize is number of items waiting in processor queue
+	MetricQueueSize = "tel.exporter.queue.size"
+	// MetricDropped counts items dropped because of queue overflow or failed export
+	MetricDropped = "tel.exporter.dropped"
+	// MetricExportDuration is duration of export request to collector in seconds
+	MetricExportDuration = "tel.exporter.export.duration"
+	// MetricExportErrors counts failed export requests by grpc code or http status
+	MetricExportErrors = "tel.exporter.export.errors"
+	// MetricSentBytes counts bytes of export requests before compression
+	MetricSentBytes = "tel.exporter.sent.bytes"
+	// MetricReconnects counts reconnects to collector
+	MetricReconnects = "tel.exporter.reconnects"
+)
+
+const (
+	SignalKey = attribute.Key("signal")
+	CodeKey   = attribute.Key("code")
+)
+
+// Metrics of single signal pipeline
+type Metrics struct {
+	meter  metric.Meter
+	signal attribute.KeyValue
+	attrs  metric.MeasurementOption
+
+	queueSize  metric.Int64ObservableGauge
+	dropped    metric.Int64Counter
+	duration   metric.Float64Histogram
+	errors     metric.Int64Counter
+	sentBytes  metric.Int64Counter
+	reconnects metric.Int64Counter
+}
+
+// New creates instruments of signal pipeline, global provider is used when mp is nil
+func New(mp metric.MeterProvider, signal string) *Metrics {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	m := &Metrics{
+		meter:  mp.Meter(instrumentationName),
+		signal: SignalKey.String(signal),
+	}
+	m.attrs = metric.WithAttributes(m.signal)
+
+	m.createMeasures()
+
+	return m
+}
+
+func (m *Metrics) createMeasures() {
+	var err error
+
+	m.queueSize, err = m.meter.Int64ObservableGauge(MetricQueueSize,
+		metric.WithDescription("Number of items waiting in processor queue"),
+	)
+	handleErr(err)
+
+	m.dropped, err = m.meter.Int64Counter(MetricDropped,
+		metric.WithDescription("Items dropped because of queue overflow or failed export"),
+	)
+	handleErr(err)
+
+	m.duration, err = m.meter.Float64Histogram(MetricExportDuration,
+		metric.WithDescription("Duration of export request to collector"),
+		metric.WithUnit("s"),
+	)
+	handleErr(err)
+
+	m.errors, err = m.meter.Int64Counter(MetricExportErrors,
+		metric.WithDescription("Failed export requests to collector"),
+	)
+	handleErr(err)
+
+	m.sentBytes, err = m.meter.Int64Counter(MetricSentBytes,
+		metric.WithDescription("Size of export requests before compression"),
+		metric.WithUnit("By"),
+	)
+	handleErr(err)
+
+	m.reconnects, err = m.meter.Int64Counter(MetricReconnects,
+		metric.WithDescription("Reconnects to collector"),
+	)
+	handleErr(err)
+}
+
+// ObserveQueue reports result of size as queue depth on each collection
+func (m *Metrics) ObserveQueue(size func() int) (metric.Registration, error) {
+	return m.meter.RegisterCallback(func(_ context.Context, obs metric.Observer) error {
+		obs.ObserveInt64(m.queueSize, int64(size()), m.attrs)
+
+		return nil
+	}, m.queueSize)
+}
+
+// Dropped adds n items to dropped counter
+func (m *Metrics) Dropped(ctx context.Context, n int) {
+	m.dropped.Add(ctx, int64(n), m.attrs)
+}
+
+// Reconnected increments reconnect counter
+func (m *Metrics) Reconnected(ctx context.Context) {
+	m.reconnects.Add(ctx, 1, m.attrs)
+}
+
+// Exported records single export request of size bytes
+func (m *Metrics) Exported(ctx context.Context, d time.Duration, size int, err error) {
+	m.exported(ctx, d, size, errorCode(err))
+}
+
+// exported records export request failed with code if it isn't empty, negative size isn't counted
+func (m *Metrics) exported(ctx context.Context, d time.Duration, size int, code string) {
+	m.duration.Record(ctx, d.Seconds(), m.attrs)
+
+	if size >= 0 {
+		m.sentBytes.Add(ctx, int64(size), m.attrs)
+	}
+
+	if code != "" {
+		m.errors.Add(ctx, 1, metric.WithAttributes(m.signal, CodeKey.String(code)))
+	}
+}
+
+// errorCode is grpc code of err, empty for nil err
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return status.Code(err).String()
+}
+
+// UnaryClientInterceptor records export requests of OTLP grpc exporters.
+// Signal is taken from grpc method package: opentelemetry.proto.collector.{logs,trace,metrics}.v1.
+// Instruments are created on the first request, so exporter of metrics may use Deferred provider
+func UnaryClientInterceptor(mp metric.MeterProvider) grpc.UnaryClientInterceptor {
+	var (
+		once    sync.Once
+		signals map[string]*Metrics
+	)
+
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		once.Do(func() {
+			signals = map[string]*Metrics{
+				SignalLogs:    New(mp, SignalLogs),
+				SignalTraces:  New(mp, SignalTraces),
+				SignalMetrics: New(mp, SignalMetrics),
+			}
+		})
+
+		if m, ok := signals[methodSignal(method)]; ok {
+			size := 0
+			if msg, ok := req.(proto.Message); ok {
+				size = proto.Size(msg)
+			}
+
+			m.Exported(ctx, time.Since(start), size, err)
+		}
+
+		return err
+	}
+}
+
+// Deferred is meter provider which is known after its users are created, e.g. exporter of metrics is created
+// before meter provider reading it. Meters are taken from provider of Set, noop meters are returned before that
+type Deferred struct {
+	embedded.MeterProvider
+
+	mp atomic.Pointer[metric.MeterProvider]
+}
+
+// Set sets provider of meters
+func (d *Deferred) Set(mp metric.MeterProvider) {
+	d.mp.Store(&mp)
+}
+
+// Meter implements metric.MeterProvider.
+func (d *Deferred) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	if mp := d.mp.Load(); mp != nil {
+		return (*mp).Meter(name, opts...)
+	}
+
+	return noop.NewMeterProvider().Meter(name, opts...)
+}
+
+// methodSignal returns signal of grpc method like /opentelemetry.proto.collector.logs.v1.LogsService/Export
+func methodSignal(method string) string {
+	const prefix = "/opentelemetry.proto.collector."
+
+	if !strings.HasPrefix(method, prefix) {
+		return ""
+	}
+
+	pkg, _, _ := strings.Cut(method[len(prefix):], ".")
+
+	switch pkg {
+	case "logs":
+		return SignalLogs
+	case "trace":
+		return SignalTraces
+	case "metrics":
+		return SignalMetrics
+	default:
+		return ""
+	}
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
@@ -0,0 +1,114 @@
+package selfmetric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+)
+
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	out := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			out[m.Name] = m.Data
+		}
+	}
+
+	return out
+}
+
+func TestMethodSignal(t *testing.T) {
+	assert.Equal(t, SignalLogs, methodSignal("/opentelemetry.proto.collector.logs.v1.LogsService/Export"))
+	assert.Equal(t, SignalTraces, methodSignal("/opentelemetry.proto.collector.trace.v1.TraceService/Export"))
+	assert.Equal(t, SignalMetrics, methodSignal("/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"))
+	assert.Empty(t, methodSignal("/grpc.health.v1.Health/Check"))
+}
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	m := New(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), SignalLogs)
+
+	reg, err := m.ObserveQueue(func() int { return 7 })
+	require.NoError(t, err)
+	defer func() { _ = reg.Unregister() }()
+
+	m.Dropped(ctx, 3)
+	m.Reconnected(ctx)
+
+	data := collect(t, reader)
+	signal := attribute.NewSet(SignalKey.String(SignalLogs))
+
+	assert.Equal(t, int64(7), data[MetricQueueSize].(metricdata.Gauge[int64]).DataPoints[0].Value)
+
+	dropped := data[MetricDropped].(metricdata.Sum[int64]).DataPoints[0]
+	assert.Equal(t, int64(3), dropped.Value)
+	assert.Equal(t, signal, dropped.Attributes)
+
+	assert.Equal(t, int64(1), data[MetricReconnects].(metricdata.Sum[int64]).DataPoints[0].Value)
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	interceptor := UnaryClientInterceptor(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	const method = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+
+	req := &collogspb.ExportLogsServiceRequest{}
+	ok := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+	failed := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "down")
+	}
+
+	require.NoError(t, interceptor(ctx, method, req, nil, nil, ok))
+	require.Error(t, interceptor(ctx, method, req, nil, nil, failed))
+	require.Error(t, interceptor(ctx, method, req, nil, nil, failed))
+
+	data := collect(t, reader)
+
+	duration := data[MetricExportDuration].(metricdata.Histogram[float64]).DataPoints[0]
+	assert.Equal(t, uint64(3), duration.Count)
+
+	exportErrors := data[MetricExportErrors].(metricdata.Sum[int64]).DataPoints[0]
+	assert.Equal(t, int64(2), exportErrors.Value)
+
+	code, _ := exportErrors.Attributes.Value(CodeKey)
+	assert.Equal(t, codes.Unavailable.String(), code.AsString())
+}
+
+func TestDeferred(t *testing.T) {
+	ctx := context.Background()
+	mp := &Deferred{}
+
+	// exporter of metrics is created before its provider
+	interceptor := UnaryClientInterceptor(mp)
+
+	reader := sdkmetric.NewManualReader()
+	mp.Set(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	ok := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return nil
+	}
+	require.NoError(t, interceptor(ctx, "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export",
+		&collogspb.ExportLogsServiceRequest{}, nil, nil, ok))
+
+	assert.Contains(t, collect(t, reader), MetricExportDuration)
+}
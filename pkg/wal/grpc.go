@@ -0,0 +1,174 @@
+package wal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var ErrUnknownMethod = errors.New("unknown grpc method")
+
+// replayTimeout limits each replayed request when connection becomes ready
+const replayTimeout = 10 * time.Second
+
+// UnaryClientInterceptor spills requests failed because collector is unreachable into w
+// and reports success to the exporter. Stored requests are replayed in order before the next request
+// and as soon as connection becomes ready. While w is not empty new requests are queued as well, so ordering is kept.
+//
+// It works with exporters built on grpc: otlptracegrpc, otlpmetricgrpc.
+func UnaryClientInterceptor(w *WAL) grpc.UnaryClientInterceptor {
+	var watch sync.Once
+
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		// headers of exporter are passed by metadata of request context
+		watch.Do(func() {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			go replayOnReady(metadata.NewOutgoingContext(context.Background(), md), w, cc, invoker, opts...)
+		})
+
+		if w.Len() == 0 {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if !Unreachable(err) {
+				return err
+			}
+
+			if spillErr := spill(w, method, msg); spillErr != nil {
+				return errors.Join(err, spillErr)
+			}
+
+			return nil
+		}
+
+		if err := spill(w, method, msg); err != nil {
+			return err
+		}
+
+		// replay failure means the request is kept in w, exporter has nothing to do with it
+		_ = w.Replay(func(data []byte) error {
+			return invoke(ctx, data, cc, invoker, opts...)
+		})
+
+		return nil
+	}
+}
+
+// replayOnReady replays w on each transition of cc to ready state until cc is closed,
+// so buffered requests don't wait for the next export
+func replayOnReady(ctx context.Context, w *WAL, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) {
+	for state := cc.GetState(); state != connectivity.Shutdown; state = cc.GetState() {
+		switch {
+		case w.Len() == 0:
+		case state == connectivity.Ready:
+			_ = w.Replay(func(data []byte) error {
+				ctx, cancel := context.WithTimeout(ctx, replayTimeout)
+				defer cancel()
+
+				return invoke(ctx, data, cc, invoker, opts...)
+			})
+		case state == connectivity.Idle:
+			// idle connection isn't dialed until the next request
+			cc.Connect()
+		}
+
+		if !cc.WaitForStateChange(context.Background(), state) {
+			return
+		}
+	}
+}
+
+// Unreachable reports whether err means collector can't be reached and request is worth keeping.
+// Canceled request isn't kept: exporter gave up on it, e.g. on shutdown.
+func Unreachable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func spill(w *WAL, method string, msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 0, len(method)+1+len(data))
+	buf = append(buf, method...)
+	buf = append(buf, '\n')
+	buf = append(buf, data...)
+
+	return w.Write(buf)
+}
+
+func invoke(ctx context.Context, data []byte, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	method, raw, ok := bytes.Cut(data, []byte{'\n'})
+	if !ok {
+		// broken entry can't be replayed, drop it
+		return nil
+	}
+
+	req, reply, err := newMessages(string(method))
+	if err != nil {
+		return nil //nolint:nilerr // entry of unknown method can't be replayed, drop it
+	}
+
+	if err = proto.Unmarshal(raw, req); err != nil {
+		return nil //nolint:nilerr // broken entry can't be replayed, drop it
+	}
+
+	err = invoker(ctx, string(method), req, reply, cc, opts...)
+	if err != nil && !Unreachable(err) && status.Code(err) != codes.Canceled {
+		// collector rejected request, there is no sense to keep it
+		return nil
+	}
+
+	return err
+}
+
+// newMessages resolves request and reply types of full grpc method name: /package.Service/Method
+func newMessages(method string) (req, reply proto.Message, err error) {
+	name := strings.ReplaceAll(strings.TrimPrefix(method, "/"), "/", ".")
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownMethod, method)
+	}
+
+	md, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownMethod, method)
+	}
+
+	in, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return in.New().Interface(), out.New().Interface(), nil
+}
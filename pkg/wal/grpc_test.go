@@ -0,0 +1,166 @@
+package wal
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+type traceCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	unavailable atomic.Bool
+
+	mu    sync.Mutex
+	names []string
+}
+
+func (c *traceCollector) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	if c.unavailable.Load() {
+		return nil, status.Error(codes.Unavailable, "collector is down")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				c.names = append(c.names, s.Name)
+			}
+		}
+	}
+
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func exportRequest(name string) *coltracepb.ExportTraceServiceRequest {
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{{Name: name}},
+			}},
+		}},
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	ctx := context.Background()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	mc := &traceCollector{}
+	srv := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(srv, mc)
+
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	w, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	cc, err := grpc.NewClient(ln.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(w)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cc.Close() })
+
+	client := coltracepb.NewTraceServiceClient(cc)
+
+	mc.unavailable.Store(true)
+
+	_, err = client.Export(ctx, exportRequest("a"))
+	require.NoError(t, err)
+	_, err = client.Export(ctx, exportRequest("b"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, w.Len())
+
+	mc.unavailable.Store(false)
+
+	_, err = client.Export(ctx, exportRequest("c"))
+	require.NoError(t, err)
+	assert.Zero(t, w.Len())
+	assert.Equal(t, []string{"a", "b", "c"}, mc.names)
+}
+
+func TestUnaryClientInterceptor_ReplayOnReady(t *testing.T) {
+	ctx := context.Background()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	w, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	cc, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.Config{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond},
+			MinConnectTimeout: 100 * time.Millisecond,
+		}),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(w)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cc.Close() })
+
+	client := coltracepb.NewTraceServiceClient(cc)
+
+	_, err = client.Export(ctx, exportRequest("a"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, w.Len())
+
+	ln, err = net.Listen("tcp", addr)
+	require.NoError(t, err)
+
+	mc := &traceCollector{}
+	srv := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(srv, mc)
+
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	// no more exports, buffer is replayed once connection is ready
+	assert.Eventually(t, func() bool { return w.Len() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	assert.Equal(t, []string{"a"}, mc.names)
+}
+
+func TestUnaryClientInterceptor_Canceled(t *testing.T) {
+	w, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	interceptor := UnaryClientInterceptor(w)
+	canceled := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Canceled, "context canceled")
+	}
+
+	cc, err := grpc.NewClient("127.0.0.1:0", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cc.Close() })
+
+	err = interceptor(context.Background(), "/opentelemetry.proto.collector.trace.v1.TraceService/Export",
+		exportRequest("a"), &coltracepb.ExportTraceServiceResponse{}, cc, canceled)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assert.Zero(t, w.Len())
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package wal
+
+import "os"
+
+// lockFile is no-op where flock isn't available, dir must not be shared by processes
+func lockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package wal
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile takes exclusive advisory lock of f, it's released when f is closed or process exits
+func lockFile(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrLocked
+	}
+
+	return err
+}
@@ -0,0 +1,353 @@
+// Package wal implements bounded on-disk queue of serialized export requests.
+// It keeps requests while collector is unreachable and replays them in order.
+package wal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fileExt  = ".wal"
+	lockName = ".lock"
+)
+
+var (
+	ErrTooLarge = errors.New("entry exceeds wal max size")
+	ErrClosed   = errors.New("wal is closed")
+	ErrLocked   = errors.New("wal dir is used by another process")
+)
+
+// PartialError is returned by Replay callback when only part of entry was sent,
+// entry is replaced by Rest and replay stops with Err
+type PartialError struct {
+	Rest []byte
+	Err  error
+}
+
+func (e *PartialError) Error() string { return e.Err.Error() }
+
+func (e *PartialError) Unwrap() error { return e.Err }
+
+type Option func(*Options)
+
+func DefaultOptions() Options {
+	return Options{
+		MaxSize: 100 << 20,
+		MaxAge:  time.Hour,
+	}
+}
+
+type Options struct {
+	// MaxSize limits total size of entries in bytes, the oldest entries are dropped to fit new one
+	MaxSize int64
+	// MaxAge drops entries which are older during replay, zero disables the limit
+	MaxAge time.Duration
+}
+
+func WithMaxSize(size int64) Option {
+	return func(opts *Options) {
+		opts.MaxSize = size
+	}
+}
+
+func WithMaxAge(age time.Duration) Option {
+	return func(opts *Options) {
+		opts.MaxAge = age
+	}
+}
+
+type entry struct {
+	seq  uint64
+	time time.Time
+	size int64
+}
+
+func (e entry) name() string {
+	return fmt.Sprintf("%020d-%d%s", e.seq, e.time.UnixNano(), fileExt)
+}
+
+// WAL is directory where each entry is stored as separate file named by sequence number and write time
+type WAL struct {
+	dir  string
+	opts Options
+
+	// lock is held until Close, so dir isn't shared by running processes
+	lock *os.File
+
+	mu      sync.Mutex
+	entries []entry
+	size    int64
+	seq     uint64
+	dropped uint64
+	closed  bool
+
+	replayMu sync.Mutex
+}
+
+// Open creates dir if needed, locks it and loads entries left by previous run.
+// ErrLocked is returned if dir is opened by another process or another WAL.
+func Open(dir string, options ...Option) (*WAL, error) {
+	opts := DefaultOptions()
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create wal dir: %w", err)
+	}
+
+	lock, err := os.OpenFile(filepath.Join(dir, lockName), os.O_CREATE|os.O_RDWR, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("open wal lock: %w", err)
+	}
+
+	if err = lockFile(lock); err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("lock wal dir: %w", err)
+	}
+
+	w := &WAL{dir: dir, opts: opts, lock: lock}
+	if err = w.load(); err != nil {
+		_ = lock.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *WAL) load() error {
+	files, err := os.ReadDir(w.dir)
+	if err != nil {
+		return fmt.Errorf("read wal dir: %w", err)
+	}
+
+	for _, f := range files {
+		// partially written entry of crashed process
+		if strings.HasSuffix(f.Name(), fileExt+".tmp") {
+			_ = os.Remove(filepath.Join(w.dir, f.Name()))
+			continue
+		}
+
+		e, ok := parseName(f.Name())
+		if !ok || f.IsDir() {
+			continue
+		}
+
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+
+		e.size = info.Size()
+		w.entries = append(w.entries, e)
+		w.size += e.size
+	}
+
+	sort.Slice(w.entries, func(i, j int) bool {
+		return w.entries[i].seq < w.entries[j].seq
+	})
+
+	if n := len(w.entries); n > 0 {
+		w.seq = w.entries[n-1].seq
+	}
+
+	return nil
+}
+
+func parseName(name string) (entry, bool) {
+	if !strings.HasSuffix(name, fileExt) {
+		return entry{}, false
+	}
+
+	seq, ts, ok := strings.Cut(strings.TrimSuffix(name, fileExt), "-")
+	if !ok {
+		return entry{}, false
+	}
+
+	s, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return entry{}, false
+	}
+
+	t, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return entry{}, false
+	}
+
+	return entry{seq: s, time: time.Unix(0, t)}, true
+}
+
+// Write appends data to the tail of queue.
+// The oldest entries are dropped when MaxSize is exceeded.
+func (w *WAL) Write(data []byte) error {
+	size := int64(len(data))
+	if w.opts.MaxSize > 0 && size > w.opts.MaxSize {
+		return ErrTooLarge
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+
+	for w.opts.MaxSize > 0 && len(w.entries) > 0 && w.size+size > w.opts.MaxSize {
+		w.removeHead()
+		w.dropped++
+	}
+
+	e := entry{seq: w.seq + 1, time: time.Now(), size: size}
+
+	tmp := filepath.Join(w.dir, e.name()+".tmp")
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return fmt.Errorf("write wal entry: %w", err)
+	}
+
+	if err := os.Rename(tmp, filepath.Join(w.dir, e.name())); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write wal entry: %w", err)
+	}
+
+	w.seq = e.seq
+	w.size += size
+	w.entries = append(w.entries, e)
+
+	return nil
+}
+
+// Replay passes entries to fn from the oldest one.
+// Entry is removed when fn succeeds, on error replay stops and entry is kept for next attempt.
+// Entries older than MaxAge are dropped without calling fn.
+func (w *WAL) Replay(fn func(data []byte) error) error {
+	w.replayMu.Lock()
+	defer w.replayMu.Unlock()
+
+	for {
+		e, ok := w.head()
+		if !ok {
+			return nil
+		}
+
+		data, err := os.ReadFile(filepath.Join(w.dir, e.name()))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("read wal entry: %w", err)
+		}
+
+		// file is removed by concurrent Write which made space for new entry
+		if err == nil {
+			if err = fn(data); err != nil {
+				var partial *PartialError
+				if errors.As(err, &partial) {
+					return errors.Join(partial.Err, w.replaceHead(e, partial.Rest))
+				}
+
+				return err
+			}
+		}
+
+		w.mu.Lock()
+		if len(w.entries) > 0 && w.entries[0].seq == e.seq {
+			w.removeHead()
+		}
+		w.mu.Unlock()
+	}
+}
+
+// replaceHead rewrites data of e if it's still the oldest entry
+func (w *WAL) replaceHead(e entry, data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.entries) == 0 || w.entries[0].seq != e.seq {
+		return nil
+	}
+
+	tmp := filepath.Join(w.dir, e.name()+".tmp")
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return fmt.Errorf("write wal entry: %w", err)
+	}
+
+	if err := os.Rename(tmp, filepath.Join(w.dir, e.name())); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write wal entry: %w", err)
+	}
+
+	w.size += int64(len(data)) - w.entries[0].size
+	w.entries[0].size = int64(len(data))
+
+	return nil
+}
+
+// head returns the oldest entry which is not expired
+func (w *WAL) head() (entry, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.entries) > 0 {
+		e := w.entries[0]
+		if w.opts.MaxAge <= 0 || time.Since(e.time) <= w.opts.MaxAge {
+			return e, true
+		}
+
+		w.removeHead()
+		w.dropped++
+	}
+
+	return entry{}, false
+}
+
+// removeHead should be called under mu
+func (w *WAL) removeHead() {
+	e := w.entries[0]
+	w.entries = w.entries[1:]
+	w.size -= e.size
+
+	_ = os.Remove(filepath.Join(w.dir, e.name()))
+}
+
+// Len returns number of entries waiting for replay
+func (w *WAL) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.entries)
+}
+
+// Size returns total size of entries waiting for replay in bytes
+func (w *WAL) Size() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.size
+}
+
+// Dropped returns number of entries dropped because of size or age limits
+func (w *WAL) Dropped() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.dropped
+}
+
+// Close prevents further writes and unlocks dir, entries are kept on disk for the next Open
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+
+	return w.lock.Close()
+}
@@ -0,0 +1,137 @@
+package wal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func replayAll(t *testing.T, w *WAL) []string {
+	var out []string
+	require.NoError(t, w.Replay(func(data []byte) error {
+		out = append(out, string(data))
+		return nil
+	}))
+
+	return out
+}
+
+func TestWAL_ReplayOrder(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := Open(dir)
+	require.NoError(t, err)
+
+	for _, v := range []string{"a", "b", "c"} {
+		require.NoError(t, w.Write([]byte(v)))
+	}
+
+	require.NoError(t, w.Close())
+	assert.ErrorIs(t, w.Write([]byte("d")), ErrClosed)
+
+	// entries survive restart
+	w, err = Open(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, w.Len())
+	assert.Equal(t, int64(3), w.Size())
+
+	require.NoError(t, w.Write([]byte("d")))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, replayAll(t, w))
+	assert.Zero(t, w.Len())
+	assert.Zero(t, w.Size())
+
+	// only lock of dir is left
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, lockName, files[0].Name())
+}
+
+func TestWAL_Lock(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := Open(dir)
+	require.NoError(t, err)
+
+	_, err = Open(dir)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+
+	w, err = Open(dir)
+	require.NoError(t, err)
+	assert.NoError(t, w.Close())
+}
+
+func TestWAL_ReplayError(t *testing.T) {
+	w, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write([]byte("a")))
+	require.NoError(t, w.Write([]byte("b")))
+
+	errFailed := errors.New("failed")
+	err = w.Replay(func(data []byte) error {
+		if string(data) == "b" {
+			return errFailed
+		}
+
+		return nil
+	})
+	assert.ErrorIs(t, err, errFailed)
+	assert.Equal(t, []string{"b"}, replayAll(t, w))
+}
+
+func TestWAL_ReplayPartial(t *testing.T) {
+	w, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write([]byte("abc")))
+	require.NoError(t, w.Write([]byte("d")))
+
+	errFailed := errors.New("failed")
+	err = w.Replay(func(data []byte) error {
+		return &PartialError{Rest: data[2:], Err: errFailed}
+	})
+	assert.ErrorIs(t, err, errFailed)
+	assert.Equal(t, int64(2), w.Size())
+	assert.Equal(t, []string{"c", "d"}, replayAll(t, w))
+}
+
+func TestWAL_MaxSize(t *testing.T) {
+	w, err := Open(t.TempDir(), WithMaxSize(4))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, w.Write([]byte("large")), ErrTooLarge)
+
+	for _, v := range []string{"ab", "cd", "ef"} {
+		require.NoError(t, w.Write([]byte(v)))
+	}
+
+	assert.Equal(t, uint64(1), w.Dropped())
+	assert.Equal(t, []string{"cd", "ef"}, replayAll(t, w))
+}
+
+func TestWAL_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+
+	old := entry{seq: 1, time: time.Now().Add(-time.Hour)}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, old.name()), []byte("old"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.wal.tmp"), []byte("tmp"), 0o600))
+
+	w, err := Open(dir, WithMaxAge(time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, w.Write([]byte("new")))
+
+	assert.Equal(t, []string{"new"}, replayAll(t, w))
+	assert.Equal(t, uint64(1), w.Dropped())
+
+	_, err = os.Stat(filepath.Join(dir, "broken.wal.tmp"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
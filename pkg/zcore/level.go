@@ -0,0 +1,30 @@
+package zcore
+
+import "go.uber.org/zap/zapcore"
+
+// NewLevel creates a Core that writes entries enabled by enab to core regardless of core own level,
+// e.g. debug logs of single request with info level outputs
+func NewLevel(core zapcore.Core, enab zapcore.LevelEnabler) zapcore.Core {
+	return &levelCore{Core: core, enab: enab}
+}
+
+type levelCore struct {
+	zapcore.Core
+	enab zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.enab.Enabled(lvl) || c.Core.Enabled(lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), enab: c.enab}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enab.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return c.Core.Check(ent, ce)
+}
@@ -13,11 +13,11 @@ import (
 type Core struct {
 	trace.Span
 	enc    *attrencoder.AtrEncoder
-	lvl    zapcore.Level
+	lvl    zapcore.LevelEnabler
 	config *config
 }
 
-func New(lvl zapcore.Level, span trace.Span, opts ...Option) zapcore.Core {
+func New(lvl zapcore.LevelEnabler, span trace.Span, opts ...Option) zapcore.Core {
 	c := &config{}
 	for _, opt := range opts {
 		opt.apply(c)
@@ -90,4 +90,4 @@ func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.Check
 
 func (c Core) Sync() error { return nil }
 
-func (c Core) Enabled(lvl zapcore.Level) bool { return lvl >= c.lvl }
+func (c Core) Enabled(lvl zapcore.Level) bool { return c.lvl.Enabled(lvl) }
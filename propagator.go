@@ -0,0 +1,53 @@
+package tel
+
+import (
+	"github.com/pkg/errors"
+	sdktrace "github.com/tel-io/tel/v2/sdk/trace"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var ErrUnknownPropagator = errors.New("unknown propagator")
+
+// Propagators supported by OTEL_PROPAGATORS
+const (
+	PropagatorTraceContext = "tracecontext"
+	PropagatorBaggage      = "baggage"
+	// PropagatorB3 injects single b3 header, extracts single and multiple headers
+	PropagatorB3 = "b3"
+	// PropagatorB3Multi injects x-b3-* headers, extracts single and multiple headers
+	PropagatorB3Multi = "b3multi"
+	PropagatorJaeger  = "jaeger"
+	PropagatorNone    = "none"
+)
+
+// propagator composes propagators in order of config, none or empty list gives no-op propagator.
+// Forced sampling goes last as it reads baggage extracted before
+func (c *OtelConfig) propagator() (propagation.TextMapPropagator, error) {
+	list := make([]propagation.TextMapPropagator, 0, len(c.Propagators)+1)
+
+	for _, name := range c.Propagators {
+		switch name {
+		case PropagatorTraceContext:
+			list = append(list, propagation.TraceContext{})
+		case PropagatorBaggage:
+			list = append(list, propagation.Baggage{})
+		case PropagatorB3:
+			list = append(list, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case PropagatorB3Multi:
+			list = append(list, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case PropagatorJaeger:
+			list = append(list, jaeger.Jaeger{})
+		case PropagatorNone:
+		default:
+			return nil, errors.WithMessagef(ErrUnknownPropagator, "%q", name)
+		}
+	}
+
+	if c.Traces.ForceSample.Enable {
+		list = append(list, sdktrace.ForceSampling{Header: c.Traces.ForceSample.Header})
+	}
+
+	return propagation.NewCompositeTextMapPropagator(list...), nil
+}
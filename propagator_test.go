@@ -0,0 +1,122 @@
+package tel
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOtelConfig_Propagator(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+
+	ctx := baggage.ContextWithBaggage(trace.ContextWithSpanContext(context.Background(), sc), bag)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		baggage bool
+	}{
+		{
+			name:    PropagatorTraceContext,
+			headers: map[string]string{"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		},
+		{
+			name:    PropagatorBaggage,
+			headers: map[string]string{"Baggage": "tenant=acme"},
+			baggage: true,
+		},
+		{
+			name:    PropagatorB3,
+			headers: map[string]string{"B3": "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"},
+		},
+		{
+			name: PropagatorB3Multi,
+			headers: map[string]string{
+				"X-B3-Traceid": "4bf92f3577b34da6a3ce929d0e0e4736",
+				"X-B3-Spanid":  "00f067aa0ba902b7",
+				"X-B3-Sampled": "1",
+			},
+		},
+		{
+			name:    PropagatorJaeger,
+			headers: map[string]string{"Uber-Trace-Id": "4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := OtelConfig{Propagators: []string{tt.name}}
+
+			p, err := cfg.propagator()
+			require.NoError(t, err)
+
+			t.Run("inject", func(t *testing.T) {
+				carrier := propagation.HeaderCarrier(http.Header{})
+				p.Inject(ctx, carrier)
+
+				for k, v := range tt.headers {
+					assert.Equal(t, v, carrier.Get(k), k)
+				}
+			})
+
+			t.Run("extract", func(t *testing.T) {
+				carrier := propagation.HeaderCarrier(http.Header{})
+				for k, v := range tt.headers {
+					carrier.Set(k, v)
+				}
+
+				extracted := p.Extract(context.Background(), carrier)
+
+				if tt.baggage {
+					assert.Equal(t, "acme", baggage.FromContext(extracted).Member("tenant").Value())
+					return
+				}
+
+				got := trace.SpanContextFromContext(extracted)
+				assert.Equal(t, sc.TraceID(), got.TraceID())
+				assert.Equal(t, sc.SpanID(), got.SpanID())
+				assert.True(t, got.IsSampled())
+			})
+		})
+	}
+
+	t.Run(PropagatorNone, func(t *testing.T) {
+		p, err := (&OtelConfig{Propagators: []string{PropagatorNone}}).propagator()
+		require.NoError(t, err)
+
+		carrier := propagation.HeaderCarrier(http.Header{})
+		p.Inject(ctx, carrier)
+		assert.Empty(t, carrier.Keys())
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("OTEL_PROPAGATORS", "tracecontext,b3multi,jaeger")
+
+		cfg := GetConfigFromEnv()
+		assert.Equal(t, []string{PropagatorTraceContext, PropagatorB3Multi, PropagatorJaeger}, cfg.Propagators)
+
+		p, err := cfg.OtelConfig.propagator()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"traceparent", "tracestate", "x-b3-traceid", "x-b3-spanid", "x-b3-sampled",
+			"x-b3-flags", "uber-trace-id"}, p.Fields())
+	})
+
+	_, err = (&OtelConfig{Propagators: []string{"xray"}}).propagator()
+	assert.ErrorIs(t, err, ErrUnknownPropagator)
+}
@@ -0,0 +1,34 @@
+package metric
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+)
+
+// Exemplar filters, values of OTEL_METRICS_EXEMPLAR_FILTER
+const (
+	// ExemplarFilterAlwaysOn records exemplars of all measurements
+	ExemplarFilterAlwaysOn = "always_on"
+	// ExemplarFilterTraceBased records exemplars of measurements made in context of sampled span
+	ExemplarFilterTraceBased = "trace_based"
+	// ExemplarFilterAlwaysOff disables exemplars
+	ExemplarFilterAlwaysOff = "always_off"
+)
+
+var ErrUnknownExemplarFilter = errors.New("unknown exemplar filter")
+
+// ExemplarFilter returns sdk filter by its name, pass it to provider with metric.WithExemplarFilter
+func ExemplarFilter(name string) (exemplar.Filter, error) {
+	switch name {
+	case ExemplarFilterAlwaysOn:
+		return exemplar.AlwaysOnFilter, nil
+	case ExemplarFilterTraceBased:
+		return exemplar.TraceBasedFilter, nil
+	case ExemplarFilterAlwaysOff:
+		return exemplar.AlwaysOffFilter, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownExemplarFilter, name)
+}
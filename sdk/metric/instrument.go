@@ -4,17 +4,65 @@ import (
 	"context"
 
 	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
+// addOptions applies cardinality detector to attributes of measurement, false means measurement is dropped
+func addOptions(
+	ctx context.Context,
+	cardinalityDetector cardinalitydetector.Detector,
+	options []metric.AddOption,
+) ([]metric.AddOption, bool) {
+	attrs := metric.NewAddConfig(options).Attributes()
+
+	limited, ok := cardinalityDetector.LimitAttrs(ctx, attrs.ToSlice())
+	if !ok || limited == nil {
+		return options, ok
+	}
+
+	return []metric.AddOption{metric.WithAttributeSet(attribute.NewSet(limited...))}, true
+}
+
+// recordOptions applies cardinality detector to attributes of measurement, false means measurement is dropped
+func recordOptions(
+	ctx context.Context,
+	cardinalityDetector cardinalitydetector.Detector,
+	options []metric.RecordOption,
+) ([]metric.RecordOption, bool) {
+	attrs := metric.NewRecordConfig(options).Attributes()
+
+	limited, ok := cardinalityDetector.LimitAttrs(ctx, attrs.ToSlice())
+	if !ok || limited == nil {
+		return options, ok
+	}
+
+	return []metric.RecordOption{metric.WithAttributeSet(attribute.NewSet(limited...))}, true
+}
+
+// observeOptions applies cardinality detector to attributes of observation, false means observation is dropped
+func observeOptions(
+	ctx context.Context,
+	cardinalityDetector cardinalitydetector.Detector,
+	options []metric.ObserveOption,
+) ([]metric.ObserveOption, bool) {
+	attrs := metric.NewObserveConfig(options).Attributes()
+
+	limited, ok := cardinalityDetector.LimitAttrs(ctx, attrs.ToSlice())
+	if !ok || limited == nil {
+		return options, ok
+	}
+
+	return []metric.ObserveOption{metric.WithAttributeSet(attribute.NewSet(limited...))}, true
+}
+
 type cdInt64Counter struct {
 	metric.Int64Counter
 	cardinalityDetector cardinalitydetector.Detector
 }
 
 func (c *cdInt64Counter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
-	attrs := metric.NewAddConfig(options).Attributes()
-	if c.cardinalityDetector.CheckAttrs(ctx, attrs.ToSlice()) {
+	if options, ok := addOptions(ctx, c.cardinalityDetector, options); ok {
 		c.Int64Counter.Add(ctx, incr, options...)
 	}
 }
@@ -25,8 +73,7 @@ type cdInt64UpDownCounter struct {
 }
 
 func (c *cdInt64UpDownCounter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
-	attrs := metric.NewAddConfig(options).Attributes()
-	if c.cardinalityDetector.CheckAttrs(ctx, attrs.ToSlice()) {
+	if options, ok := addOptions(ctx, c.cardinalityDetector, options); ok {
 		c.Int64UpDownCounter.Add(ctx, incr, options...)
 	}
 }
@@ -37,8 +84,7 @@ type cdInt64Histogram struct {
 }
 
 func (h *cdInt64Histogram) Record(ctx context.Context, x int64, options ...metric.RecordOption) {
-	attrs := metric.NewRecordConfig(options).Attributes()
-	if h.cardinalityDetector.CheckAttrs(ctx, attrs.ToSlice()) {
+	if options, ok := recordOptions(ctx, h.cardinalityDetector, options); ok {
 		h.Int64Histogram.Record(ctx, x, options...)
 	}
 }
@@ -76,8 +122,7 @@ type cdFloat64Counter struct {
 }
 
 func (c *cdFloat64Counter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
-	attrs := metric.NewAddConfig(options).Attributes()
-	if c.cardinalityDetector.CheckAttrs(ctx, attrs.ToSlice()) {
+	if options, ok := addOptions(ctx, c.cardinalityDetector, options); ok {
 		c.Float64Counter.Add(ctx, incr, options...)
 	}
 }
@@ -87,9 +132,8 @@ type cdFloat64UpDownCounter struct {
 	cardinalityDetector cardinalitydetector.Detector
 }
 
-func (c *cdFloat64UpDownCounter) Observe(ctx context.Context, incr float64, options ...metric.AddOption) {
-	attrs := metric.NewAddConfig(options).Attributes()
-	if c.cardinalityDetector.CheckAttrs(ctx, attrs.ToSlice()) {
+func (c *cdFloat64UpDownCounter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	if options, ok := addOptions(ctx, c.cardinalityDetector, options); ok {
 		c.Float64UpDownCounter.Add(ctx, incr, options...)
 	}
 }
@@ -100,8 +144,7 @@ type cdFloat64Histogram struct {
 }
 
 func (h *cdFloat64Histogram) Record(ctx context.Context, x float64, options ...metric.RecordOption) {
-	attrs := metric.NewRecordConfig(options).Attributes()
-	if h.cardinalityDetector.CheckAttrs(ctx, attrs.ToSlice()) {
+	if options, ok := recordOptions(ctx, h.cardinalityDetector, options); ok {
 		h.Float64Histogram.Record(ctx, x, options...)
 	}
 }
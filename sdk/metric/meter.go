@@ -90,6 +90,20 @@ func (int64ObservableOptionWrapper[T]) wrap(
 	return optionsWrapped
 }
 
+// overflowInstrument replaces instruments exceeding MaxInstruments in ModeOverflow by single instrument of kind,
+// options of merged instruments differ, so only callbacks are kept by caller
+func overflowInstrument[T any](
+	cardinalityDetector cardinalitydetector.Detector,
+	name, kind string,
+	options []T,
+) (string, []T) {
+	if !cardinalitydetector.IsOverflow(cardinalityDetector) {
+		return name, options
+	}
+
+	return cardinalitydetector.OverflowInstrument(kind), nil
+}
+
 func newMeter(
 	ctx context.Context,
 	delegate metric.Meter,
@@ -117,6 +131,8 @@ func (m *meter) Int64Counter(
 		return nil, errLimitExceededCardinalityDetector
 	}
 
+	name, options = overflowInstrument(cardinalityDetector, name, "int64_counter", options)
+
 	c, err := m.Meter.Int64Counter(name, options...)
 	if err != nil {
 		return nil, err
@@ -134,6 +150,8 @@ func (m *meter) Int64UpDownCounter(
 		return nil, errLimitExceededCardinalityDetector
 	}
 
+	name, options = overflowInstrument(cardinalityDetector, name, "int64_up_down_counter", options)
+
 	c, err := m.Meter.Int64UpDownCounter(name, options...)
 	if err != nil {
 		return nil, err
@@ -151,6 +169,8 @@ func (m *meter) Int64Histogram(
 		return nil, errLimitExceededCardinalityDetector
 	}
 
+	name, options = overflowInstrument(cardinalityDetector, name, "int64_histogram", options)
+
 	c, err := m.Meter.Int64Histogram(name, options...)
 	if err != nil {
 		return nil, err
@@ -170,6 +190,8 @@ func (m *meter) Int64ObservableCounter(
 
 	config := metric.NewInt64ObservableCounterConfig(options...)
 
+	name, options = overflowInstrument(cardinalityDetector, name, "int64_observable_counter", options)
+
 	options = int64ObservableCounterOptionWrapper.wrap(
 		cardinalityDetector,
 		config.Callbacks(),
@@ -195,6 +217,8 @@ func (m *meter) Int64ObservableUpDownCounter(
 
 	config := metric.NewInt64ObservableUpDownCounterConfig(options...)
 
+	name, options = overflowInstrument(cardinalityDetector, name, "int64_observable_up_down_counter", options)
+
 	options = int64ObservableUpDownCounterOptionWrapper.wrap(
 		cardinalityDetector,
 		config.Callbacks(),
@@ -220,6 +244,8 @@ func (m *meter) Int64ObservableGauge(
 
 	config := metric.NewInt64ObservableGaugeConfig(options...)
 
+	name, options = overflowInstrument(cardinalityDetector, name, "int64_observable_gauge", options)
+
 	options = int64ObservableGaugeOptionWrapper.wrap(
 		cardinalityDetector,
 		config.Callbacks(),
@@ -243,6 +269,8 @@ func (m *meter) Float64Counter(
 		return nil, errLimitExceededCardinalityDetector
 	}
 
+	name, options = overflowInstrument(cardinalityDetector, name, "float64_counter", options)
+
 	c, err := m.Meter.Float64Counter(name, options...)
 	if err != nil {
 		return nil, err
@@ -260,6 +288,8 @@ func (m *meter) Float64UpDownCounter(
 		return nil, errLimitExceededCardinalityDetector
 	}
 
+	name, options = overflowInstrument(cardinalityDetector, name, "float64_up_down_counter", options)
+
 	c, err := m.Meter.Float64UpDownCounter(name, options...)
 	if err != nil {
 		return nil, err
@@ -277,6 +307,8 @@ func (m *meter) Float64Histogram(
 		return nil, errLimitExceededCardinalityDetector
 	}
 
+	name, options = overflowInstrument(cardinalityDetector, name, "float64_histogram", options)
+
 	c, err := m.Meter.Float64Histogram(name, options...)
 	if err != nil {
 		return nil, err
@@ -296,6 +328,8 @@ func (m *meter) Float64ObservableCounter(
 
 	config := metric.NewFloat64ObservableCounterConfig(options...)
 
+	name, options = overflowInstrument(cardinalityDetector, name, "float64_observable_counter", options)
+
 	options = float64ObservableCounterOptionWrapper.wrap(
 		cardinalityDetector,
 		config.Callbacks(),
@@ -321,6 +355,8 @@ func (m *meter) Float64ObservableUpDownCounter(
 
 	config := metric.NewFloat64ObservableUpDownCounterConfig(options...)
 
+	name, options = overflowInstrument(cardinalityDetector, name, "float64_observable_up_down_counter", options)
+
 	options = float64ObservableUpDownCounterOptionWrapper.wrap(
 		cardinalityDetector,
 		config.Callbacks(),
@@ -346,6 +382,8 @@ func (m *meter) Float64ObservableGauge(
 
 	config := metric.NewFloat64ObservableGaugeConfig(options...)
 
+	name, options = overflowInstrument(cardinalityDetector, name, "float64_observable_gauge", options)
+
 	options = float64ObservableGaugeOptionWrapper.wrap(
 		cardinalityDetector,
 		config.Callbacks(),
@@ -2,10 +2,12 @@ package metric
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
 	"github.com/tel-io/tel/v2/pkg/log"
 	"go.opentelemetry.io/otel"
@@ -13,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestMeter_RegisterCallback(t *testing.T) {
@@ -67,9 +70,10 @@ func TestMeter_RegisterCallback(t *testing.T) {
 	assert.NoError(err)
 	assert.Len(data.ScopeMetrics, 1)
 
+	// observation of new value over limit is dropped, sdk doesn't report stale observations
 	err = reader.Collect(context.Background(), &data)
 	assert.NoError(err)
-	assert.Len(data.ScopeMetrics, 1)
+	assert.Empty(data.ScopeMetrics)
 
 	select {
 	case rec := <-echo:
@@ -79,3 +83,153 @@ func TestMeter_RegisterCallback(t *testing.T) {
 		assert.Fail("empty log message")
 	}
 }
+
+func TestMeter_Exemplars(t *testing.T) {
+	_, err := ExemplarFilter("sometimes")
+	assert.ErrorIs(t, err, ErrUnknownExemplarFilter)
+
+	// env is overridden by option
+	t.Setenv("OTEL_METRICS_EXEMPLAR_FILTER", ExemplarFilterAlwaysOff)
+
+	filter, err := ExemplarFilter(ExemplarFilterTraceBased)
+	require.NoError(t, err)
+
+	reader := sdkmetric.NewManualReader()
+	provider := NewMeterProvider(
+		context.Background(),
+		cardinalitydetector.Options{Enable: true, MaxCardinality: 10, MaxInstruments: 10},
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithExemplarFilter(filter),
+	)
+
+	histogram, err := provider.Meter("foo").Float64Histogram("latency")
+	assert.NoError(t, err)
+	assert.IsType(t, &cdFloat64Histogram{}, histogram)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	histogram.Record(trace.ContextWithSpanContext(context.Background(), sc), 1.5)
+	histogram.Record(context.Background(), 2.5)
+
+	data := metricdata.ResourceMetrics{}
+	assert.NoError(t, reader.Collect(context.Background(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+	require.Len(t, data.ScopeMetrics[0].Metrics, 1)
+
+	hist, ok := data.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+
+	exemplars := hist.DataPoints[0].Exemplars
+	require.Len(t, exemplars, 1, "only sampled measurement")
+	assert.Equal(t, sc.TraceID().String(), trace.TraceID(exemplars[0].TraceID).String())
+	assert.Equal(t, 1.5, exemplars[0].Value)
+}
+
+func TestMeter_Overflow(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := NewMeterProvider(
+		nil,
+		cardinalitydetector.NewOptions(
+			cardinalitydetector.WithMaxCardinality(2),
+			cardinalitydetector.WithMaxInstruments(2),
+			cardinalitydetector.WithMode(cardinalitydetector.ModeOverflow),
+			cardinalitydetector.WithLogger(log.NewLogger(log.NewEchoHandler(make(chan log.Record, 10)))),
+		),
+		sdkmetric.WithReader(reader),
+	)
+
+	meter := provider.Meter("foo")
+
+	requests, err := meter.Int64Counter("requests")
+	require.NoError(t, err)
+
+	for _, user := range []string{"a", "b", "c", "d", "a"} {
+		requests.Add(context.Background(), 1,
+			metric.WithAttributes(attribute.String("user", user), attribute.String("method", "GET")))
+	}
+
+	_, err = meter.Float64Histogram("duration")
+	require.NoError(t, err)
+
+	// instruments over limit overflow all attribute values
+	extra, err := meter.Int64Counter("extra")
+	require.NoError(t, err)
+	extra.Add(context.Background(), 3, metric.WithAttributes(attribute.String("method", "POST")))
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+
+	sums := map[string]map[string]int64{}
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		sum, ok := m.Data.(metricdata.Sum[int64])
+		if !ok {
+			continue
+		}
+
+		sums[m.Name] = map[string]int64{}
+		for _, dp := range sum.DataPoints {
+			sums[m.Name][dp.Attributes.Encoded(attribute.DefaultEncoder())] = dp.Value
+		}
+	}
+
+	assert.Equal(t, map[string]int64{
+		"method=GET,user=a":         2,
+		"method=GET,user=b":         1,
+		"method=GET,user=__other__": 2,
+	}, sums["requests"])
+	assert.Equal(t, map[string]int64{"method=__other__": 3}, sums[cardinalitydetector.OverflowInstrument("int64_counter")])
+	assert.NotContains(t, sums, "extra")
+}
+
+func TestMeter_OverflowInstruments(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := NewMeterProvider(
+		nil,
+		cardinalitydetector.NewOptions(
+			cardinalitydetector.WithMaxCardinality(10),
+			cardinalitydetector.WithMaxInstruments(3),
+			cardinalitydetector.WithMode(cardinalitydetector.ModeOverflow),
+			cardinalitydetector.WithLogger(log.NewLogger(log.NewEchoHandler(make(chan log.Record, 10)))),
+		),
+		sdkmetric.WithReader(reader),
+	)
+
+	meter := provider.Meter("foo")
+	ctx := context.Background()
+
+	for i := 0; i < 20; i++ {
+		counter, err := meter.Int64Counter(fmt.Sprintf("requests.%d", i), metric.WithDescription(fmt.Sprint(i)))
+		require.NoError(t, err)
+		counter.Add(ctx, 1)
+
+		histogram, err := meter.Float64Histogram(fmt.Sprintf("duration.%d", i), metric.WithUnit("s"))
+		require.NoError(t, err)
+		histogram.Record(ctx, 0.1)
+	}
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &data))
+	require.Len(t, data.ScopeMetrics, 1)
+
+	streams := map[string]metricdata.Aggregation{}
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		streams[m.Name] = m.Data
+	}
+
+	// MaxInstruments plus single overflow instrument of each kind
+	assert.Len(t, streams, 3+2)
+
+	counter := streams[cardinalitydetector.OverflowInstrument("int64_counter")].(metricdata.Sum[int64])
+	require.Len(t, counter.DataPoints, 1)
+	assert.Equal(t, int64(20-2), counter.DataPoints[0].Value)
+
+	histogram := streams[cardinalitydetector.OverflowInstrument("float64_histogram")].(metricdata.Histogram[float64])
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(20-1), histogram.DataPoints[0].Count)
+}
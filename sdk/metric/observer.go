@@ -15,8 +15,6 @@ type cdObserver struct {
 }
 
 func (o *cdObserver) ObserveFloat64(obsrv metric.Float64Observable, value float64, options ...metric.ObserveOption) {
-	attrs := metric.NewObserveConfig(options).Attributes()
-
 	var cardinalityDetector cardinalitydetector.Detector
 	switch t := obsrv.(type) {
 	case *cdFloat64ObservableCounter:
@@ -38,14 +36,12 @@ func (o *cdObserver) ObserveFloat64(obsrv metric.Float64Observable, value float6
 		return
 	}
 
-	if cardinalityDetector.CheckAttrs(o.stopCtx, attrs.ToSlice()) {
+	if options, ok := observeOptions(o.stopCtx, cardinalityDetector, options); ok {
 		o.Observer.ObserveFloat64(unwrapped, value, options...)
 	}
 }
 
 func (o *cdObserver) ObserveInt64(obsrv metric.Int64Observable, value int64, options ...metric.ObserveOption) {
-	attrs := metric.NewObserveConfig(options).Attributes()
-
 	var cardinalityDetector cardinalitydetector.Detector
 	switch t := obsrv.(type) {
 	case *cdInt64ObservableCounter:
@@ -67,7 +63,7 @@ func (o *cdObserver) ObserveInt64(obsrv metric.Int64Observable, value int64, opt
 		return
 	}
 
-	if cardinalityDetector.CheckAttrs(o.stopCtx, attrs.ToSlice()) {
+	if options, ok := observeOptions(o.stopCtx, cardinalityDetector, options); ok {
 		o.Observer.ObserveInt64(unwrapped, value, options...)
 	}
 }
@@ -79,8 +75,7 @@ type cdFloat64Observer struct {
 }
 
 func (o *cdFloat64Observer) Observe(value float64, options ...metric.ObserveOption) {
-	attrs := metric.NewObserveConfig(options).Attributes()
-	if o.cardinalityDetector.CheckAttrs(o.stopCtx, attrs.ToSlice()) {
+	if options, ok := observeOptions(o.stopCtx, o.cardinalityDetector, options); ok {
 		o.Float64Observer.Observe(value, options...)
 	}
 }
@@ -92,8 +87,7 @@ type cdInt64Observer struct {
 }
 
 func (o *cdInt64Observer) Observe(value int64, options ...metric.ObserveOption) {
-	attrs := metric.NewObserveConfig(options).Attributes()
-	if o.cardinalityDetector.CheckAttrs(o.stopCtx, attrs.ToSlice()) {
+	if options, ok := observeOptions(o.stopCtx, o.cardinalityDetector, options); ok {
 		o.Int64Observer.Observe(value, options...)
 	}
 }
@@ -63,6 +63,23 @@ func (p *MeterProvider) Meter(name string, opts ...metric.MeterOption) metric.Me
 	return meter
 }
 
+// CardinalityReport implements cardinalitydetector.Reporter.
+func (p *MeterProvider) CardinalityReport() []cardinalitydetector.ScopeReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	reports := make([]cardinalitydetector.ScopeReport, 0, len(p.meters))
+	for _, meter := range p.meters {
+		if report := meter.cardinalityDetectorPool.Report(); report.Scope != "" {
+			reports = append(reports, report)
+		}
+	}
+
+	cardinalitydetector.SortReports(reports)
+
+	return reports
+}
+
 // Shutdown implements metric.MeterProvider.
 func (p *MeterProvider) Shutdown(ctx context.Context) error {
 	p.mu.Lock()
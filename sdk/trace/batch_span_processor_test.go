@@ -0,0 +1,60 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2/pkg/selfmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (failingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return errors.New("collector is down")
+}
+
+func TestBatchSpanProcessorMetrics(t *testing.T) {
+	must := require.New(t)
+	ctx := context.Background()
+
+	reader := sdkmetric.NewManualReader()
+	bsp := NewBatchSpanProcessorWithMeterProvider(failingExporter{tracetest.NewInMemoryExporter()},
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()), sdktrace.WithSpanProcessor(bsp))
+
+	for range 3 {
+		_, span := tp.Tracer("test").Start(ctx, "span")
+		span.End()
+	}
+
+	must.Error(bsp.ForceFlush(ctx))
+
+	var rm metricdata.ResourceMetrics
+	must.NoError(reader.Collect(ctx, &rm))
+
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				values[m.Name] = data.DataPoints[0].Value
+			case metricdata.Gauge[int64]:
+				values[m.Name] = data.DataPoints[0].Value
+			}
+		}
+	}
+
+	must.Equal(map[string]int64{selfmetric.MetricDropped: 3, selfmetric.MetricQueueSize: 0}, values)
+
+	must.NoError(tp.Shutdown(ctx))
+}
@@ -10,7 +10,10 @@ import (
 
 	"github.com/tel-io/tel/v2/pkg/global"
 	"github.com/tel-io/tel/v2/pkg/ringbuffer"
+	"github.com/tel-io/tel/v2/pkg/selfmetric"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 
@@ -19,6 +22,23 @@ import (
 
 type DelayedSpanProcessorOption func(*delayedSpanProcessorOptions)
 
+const (
+	delayedSpanProcessorMeterName = "github.com/tel-io/tel/v2/sdk/trace"
+
+	// MetricDelayedSpans counts spans by decision taken by delayed span processor
+	MetricDelayedSpans = "tel.trace.delayed_processor.spans"
+
+	// DecisionKey is attribute key of processor decision: error, max_latency, sampled or dropped
+	DecisionKey = attribute.Key("decision")
+)
+
+var (
+	decisionError      = metric.WithAttributes(DecisionKey.String("error"))       //nolint:gochecknoglobals
+	decisionMaxLatency = metric.WithAttributes(DecisionKey.String("max_latency")) //nolint:gochecknoglobals
+	decisionSampled    = metric.WithAttributes(DecisionKey.String("sampled"))     //nolint:gochecknoglobals
+	decisionDropped    = metric.WithAttributes(DecisionKey.String("dropped"))     //nolint:gochecknoglobals
+)
+
 var defaultDelayedSpanProcessorOptions = delayedSpanProcessorOptions{ //nolint:gochecknoglobals
 	maxQueueSize:       4096,
 	maxTotalSpans:      2048,
@@ -40,6 +60,7 @@ type delayedSpanProcessorOptions struct {
 	onError                  bool
 	traceIDSampleBound       sampleBound
 	traceIDSampleBoundScoped map[string]sampleBound
+	meterProvider            metric.MeterProvider
 }
 
 func newSampleBound(fraction float64) sampleBound {
@@ -124,6 +145,13 @@ func WithTraceIDFractionScoped(fractions map[string]float64) DelayedSpanProcesso
 	}
 }
 
+// WithMeterProvider sets provider of decision counters, global provider is used by default
+func WithMeterProvider(mp metric.MeterProvider) DelayedSpanProcessorOption {
+	return func(opts *delayedSpanProcessorOptions) {
+		opts.meterProvider = mp
+	}
+}
+
 var _ sdktrace.SpanProcessor = (*delayedSpanProcessor)(nil)
 
 func NewDelayedSpanProcessor(
@@ -135,6 +163,10 @@ func NewDelayedSpanProcessor(
 		opt(&opts)
 	}
 
+	if opts.meterProvider == nil {
+		opts.meterProvider = otel.GetMeterProvider()
+	}
+
 	dsp := &delayedSpanProcessor{
 		opts:          opts,
 		exporter:      exporter,
@@ -146,6 +178,8 @@ func NewDelayedSpanProcessor(
 		stopCh:        make(chan struct{}),
 	}
 
+	dsp.createMeasures()
+
 	dsp.stopWait.Add(1)
 	go func() {
 		defer dsp.stopWait.Done()
@@ -172,6 +206,30 @@ type delayedSpanProcessor struct { //nolint:maligned
 	stopWait      sync.WaitGroup
 	stopOnce      sync.Once
 	stopCh        chan struct{}
+
+	spansCounter metric.Int64Counter
+	metrics      *selfmetric.Metrics
+	metricsQueue metric.Registration
+}
+
+func (dsp *delayedSpanProcessor) createMeasures() {
+	meter := dsp.opts.meterProvider.Meter(delayedSpanProcessorMeterName)
+
+	var err error
+
+	dsp.spansCounter, err = meter.Int64Counter(MetricDelayedSpans,
+		metric.WithDescription("Spans processed by delayed span processor by decision"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	dsp.metrics = selfmetric.New(dsp.opts.meterProvider, selfmetric.SignalTraces)
+
+	dsp.metricsQueue, err = dsp.metrics.ObserveQueue(func() int { return len(dsp.queue) })
+	if err != nil {
+		otel.Handle(err)
+	}
 }
 
 type traceMetadata struct {
@@ -201,6 +259,11 @@ func (dsp *delayedSpanProcessor) Shutdown(ctx context.Context) error {
 					otel.Handle(err)
 				}
 			}
+			if dsp.metricsQueue != nil {
+				if err := dsp.metricsQueue.Unregister(); err != nil {
+					otel.Handle(err)
+				}
+			}
 			close(wait)
 		}()
 
@@ -362,6 +425,7 @@ func (dsp *delayedSpanProcessor) enqueueDrop(_ context.Context, span sdktrace.Re
 		return true
 	default:
 		atomic.AddUint32(&dsp.dropped, 1)
+		dsp.metrics.Dropped(context.Background(), 1)
 	}
 
 	return false
@@ -448,6 +512,17 @@ func (dsp *delayedSpanProcessor) exportSpans(ctx context.Context) error { //noli
 				totalShouldSample += spansLen
 			}
 
+			switch {
+			case isError:
+				dsp.spansCounter.Add(ctx, int64(spansLen), decisionError)
+			case isMaxLatency:
+				dsp.spansCounter.Add(ctx, int64(spansLen), decisionMaxLatency)
+			case shouldSample:
+				dsp.spansCounter.Add(ctx, int64(spansLen), decisionSampled)
+			default:
+				dsp.spansCounter.Add(ctx, int64(spansLen), decisionDropped)
+			}
+
 			if isError || isMaxLatency || shouldSample {
 				batch = append(batch, spans...)
 			}
@@ -475,7 +550,12 @@ func (dsp *delayedSpanProcessor) exportSpans(ctx context.Context) error { //noli
 		log.Int64("total_should_sample", int64(totalShouldSample)),
 	)
 
-	return dsp.exporter.ExportSpans(ctx, batch)
+	if err := dsp.exporter.ExportSpans(ctx, batch); err != nil {
+		dsp.metrics.Dropped(ctx, len(batch))
+		return err
+	}
+
+	return nil
 }
 
 func (dsp *delayedSpanProcessor) processQueue() { //nolint:gocognit,cyclop
@@ -0,0 +1,65 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestDelayedSpanProcessorDecisions(t *testing.T) {
+	must := require.New(t)
+	ctx := context.Background()
+
+	reader := sdkmetric.NewManualReader()
+	exporter := tracetest.NewInMemoryExporter()
+	dsp := NewDelayedSpanProcessor(exporter,
+		WithMaxLatency(time.Hour),
+		WithOnError(true),
+		WithTraceIDFraction(0),
+		WithTraceIDFractionScoped(map[string]float64{"always": 1}),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()), sdktrace.WithSpanProcessor(dsp))
+
+	_, span := tp.Tracer("test").Start(ctx, "error")
+	span.SetStatus(codes.Error, "failed")
+	span.End()
+
+	_, span = tp.Tracer("test").Start(ctx, "dropped")
+	span.End()
+
+	_, span = tp.Tracer("always").Start(ctx, "sampled")
+	span.End()
+
+	must.NoError(dsp.ForceFlush(ctx))
+	must.Len(exporter.GetSpans(), 2)
+
+	var rm metricdata.ResourceMetrics
+	must.NoError(reader.Collect(ctx, &rm))
+
+	decisions := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != MetricDelayedSpans {
+				continue
+			}
+
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				v, _ := dp.Attributes.Value(DecisionKey)
+				decisions[v.AsString()] = dp.Value
+			}
+		}
+	}
+
+	must.Equal(map[string]int64{"error": 1, "sampled": 1, "dropped": 1}, decisions)
+
+	must.NoError(tp.Shutdown(ctx))
+}
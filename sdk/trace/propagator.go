@@ -0,0 +1,59 @@
+package trace
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// BaggageForceSample is baggage key forcing sampling of the whole trace, e.g. baggage: tel.force_sample=1
+const BaggageForceSample = "tel.force_sample"
+
+var _ propagation.TextMapPropagator = ForceSampling{}
+
+// ForceSampling propagates forced sampling decision of ContextWithSpanSampling.
+// Extract forces sampling if Header or BaggageForceSample baggage member is 1 or true,
+// so it goes after baggage propagator in composite one. Inject sets Header of forced context
+type ForceSampling struct {
+	// Header is optional, only baggage is used if empty
+	Header string
+}
+
+// Inject implements propagation.TextMapPropagator.
+func (p ForceSampling) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	if p.Header == "" {
+		return
+	}
+
+	if forced, ok := SpanSamplingFromContext(ctx); ok && forced {
+		carrier.Set(p.Header, "1")
+	}
+}
+
+// Extract implements propagation.TextMapPropagator.
+func (p ForceSampling) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if p.Header != "" && isForced(carrier.Get(p.Header)) {
+		return ContextWithSpanSampling(ctx, true)
+	}
+
+	if isForced(baggage.FromContext(ctx).Member(BaggageForceSample).Value()) {
+		return ContextWithSpanSampling(ctx, true)
+	}
+
+	return ctx
+}
+
+// Fields implements propagation.TextMapPropagator.
+func (p ForceSampling) Fields() []string {
+	if p.Header == "" {
+		return nil
+	}
+
+	return []string{strings.ToLower(p.Header)}
+}
+
+func isForced(v string) bool {
+	return v == "1" || strings.EqualFold(v, "true")
+}
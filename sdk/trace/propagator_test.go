@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestForceSampling(t *testing.T) {
+	p := ForceSampling{Header: "X-Force-Sample"}
+	assert.Equal(t, []string{"x-force-sample"}, p.Fields())
+
+	for name, tt := range map[string]struct {
+		header  string
+		baggage string
+		forced  bool
+	}{
+		"header":         {header: "1", forced: true},
+		"header true":    {header: "TRUE", forced: true},
+		"baggage":        {baggage: BaggageForceSample + "=1", forced: true},
+		"not forced":     {header: "0", baggage: BaggageForceSample + "=0"},
+		"not propagated": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.baggage != "" {
+				b, err := baggage.Parse(tt.baggage)
+				assert.NoError(t, err)
+				ctx = baggage.ContextWithBaggage(ctx, b)
+			}
+
+			carrier := propagation.HeaderCarrier(http.Header{})
+			if tt.header != "" {
+				carrier.Set("X-Force-Sample", tt.header)
+			}
+
+			ctx = p.Extract(ctx, carrier)
+
+			forced, _ := SpanSamplingFromContext(ctx)
+			assert.Equal(t, tt.forced, forced)
+
+			out := propagation.HeaderCarrier(http.Header{})
+			p.Inject(ctx, out)
+			assert.Equal(t, tt.forced, out.Get("X-Force-Sample") == "1")
+		})
+	}
+
+	t.Run("baggage only", func(t *testing.T) {
+		out := propagation.HeaderCarrier(http.Header{})
+		ForceSampling{}.Inject(ContextWithSpanSampling(context.Background(), true), out)
+		assert.Empty(t, out.Keys())
+	})
+}
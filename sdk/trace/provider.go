@@ -53,6 +53,7 @@ func (p *TracerProvider) Tracer(name string, options ...trace.TracerOption) trac
 	}
 
 	tracer := newTracer(
+		name,
 		p.TracerProvider.Tracer(name, options...),
 		cardinalitydetector.NewPool(p.stopCtx, name, p.cardinalityDetectorOptions),
 	)
@@ -62,6 +63,23 @@ func (p *TracerProvider) Tracer(name string, options ...trace.TracerOption) trac
 	return tracer
 }
 
+// CardinalityReport implements cardinalitydetector.Reporter.
+func (p *TracerProvider) CardinalityReport() []cardinalitydetector.ScopeReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	reports := make([]cardinalitydetector.ScopeReport, 0, len(p.tracers))
+	for _, tracer := range p.tracers {
+		if report := tracer.cardinalityDetectorPool.Report(); report.Scope != "" {
+			reports = append(reports, report)
+		}
+	}
+
+	cardinalitydetector.SortReports(reports)
+
+	return reports
+}
+
 // Shutdown implements trace.TracerProvider.
 func (p *TracerProvider) Shutdown(ctx context.Context) error {
 	p.mu.Lock()
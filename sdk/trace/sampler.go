@@ -4,6 +4,7 @@ import (
 	"context"
 
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type shouldSampleKey struct{}
@@ -12,6 +13,17 @@ func ContextWithSpanSampling(ctx context.Context, shouldSample bool) context.Con
 	return context.WithValue(ctx, shouldSampleKey{}, shouldSample)
 }
 
+// SpanSamplingFromContext returns decision of ContextWithSpanSampling, ok is false if it isn't set
+func SpanSamplingFromContext(ctx context.Context) (shouldSample, ok bool) {
+	if ctx == nil {
+		return false, false
+	}
+
+	shouldSample, ok = ctx.Value(shouldSampleKey{}).(bool)
+
+	return shouldSample, ok
+}
+
 func NewSampler(fallback sdktrace.Sampler) *Sampler {
 	return &Sampler{fallback: fallback}
 }
@@ -23,10 +35,12 @@ type Sampler struct {
 // ShouldSample checks the context for shouldSampleKey and returns a sampling decision based on that.
 // Otherwise, it delegates to the fallback sampler.
 func (s *Sampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
-	if shouldSample, ok := params.ParentContext.Value(shouldSampleKey{}).(bool); ok && shouldSample {
-		return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample}
+	tracestate := trace.SpanContextFromContext(params.ParentContext).TraceState()
+
+	if shouldSample, ok := SpanSamplingFromContext(params.ParentContext); ok && shouldSample {
+		return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample, Tracestate: tracestate}
 	} else if ok {
-		return sdktrace.SamplingResult{Decision: sdktrace.Drop}
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: tracestate}
 	}
 
 	return s.fallback.ShouldSample(params)
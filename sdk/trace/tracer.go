@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+	"github.com/tel-io/tel/v2/pkg/samplers"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/tel-io/tel/v2/pkg/log"
@@ -12,17 +14,20 @@ import (
 var _ trace.Tracer = (*tracer)(nil)
 
 func newTracer(
+	name string,
 	delegate trace.Tracer,
 	cardinalityDetectorPool cardinalitydetector.Pool,
 ) *tracer {
 	return &tracer{
 		Tracer:                  delegate,
+		name:                    name,
 		cardinalityDetectorPool: cardinalityDetectorPool,
 	}
 }
 
 type tracer struct {
 	trace.Tracer
+	name                    string
 	cardinalityDetectorPool cardinalitydetector.Pool
 }
 
@@ -32,17 +37,93 @@ func (t *tracer) Start(
 	spanName string,
 	opts ...trace.SpanStartOption,
 ) (context.Context, trace.Span) {
-	_, ok := t.cardinalityDetectorPool.Lookup(ctx, spanName)
+	cardinalityDetector, ok := t.cardinalityDetectorPool.Lookup(ctx, spanName)
 	if !ok {
 		return ctx, trace.SpanFromContext(nil)
 	}
 
-	ctx, span := t.Tracer.Start(ctx, spanName, opts...)
+	// span names exceeding limit in overflow mode keep trace structure
+	if cardinalitydetector.IsOverflow(cardinalityDetector) {
+		spanName = cardinalitydetector.OverflowValue
+	} else {
+		opts = limitAttrs(ctx, cardinalityDetector, opts)
+	}
+
+	// sampling parameters have no instrumentation scope, rule based sampler reads it from context
+	ctx, span := t.Tracer.Start(samplers.ContextWithScope(ctx, t.name), spanName, opts...)
 	ctx = log.AppendLoggerCtx(ctx, span)
 
 	return ctx, span
 }
 
+// limitAttrs applies detector of span name to start attributes, attributes set after start aren't limited.
+// Span is kept in drop mode, only attributes with values over limit are dropped.
+func limitAttrs(
+	ctx context.Context,
+	cardinalityDetector cardinalitydetector.Detector,
+	opts []trace.SpanStartOption,
+) []trace.SpanStartOption {
+	if len(opts) == 0 {
+		return opts
+	}
+
+	config := trace.NewSpanStartConfig(opts...)
+
+	attrs := config.Attributes()
+	if len(attrs) == 0 {
+		return opts
+	}
+
+	limited, ok := cardinalityDetector.LimitAttrs(ctx, attrs)
+	if ok && limited == nil {
+		return opts
+	}
+
+	if !ok {
+		limited = dropAttrs(ctx, cardinalityDetector, attrs)
+	}
+
+	// options are built from config, so attributes given by several options are replaced at once
+	optsLimited := []trace.SpanStartOption{
+		trace.WithSpanKind(config.SpanKind()),
+		trace.WithLinks(config.Links()...),
+		trace.WithAttributes(limited...),
+	}
+
+	if ts := config.Timestamp(); !ts.IsZero() {
+		optsLimited = append(optsLimited, trace.WithTimestamp(ts))
+	}
+
+	if config.NewRoot() {
+		optsLimited = append(optsLimited, trace.WithNewRoot())
+	}
+
+	return optsLimited
+}
+
+// dropAttrs checks attributes one by one and returns those within limit
+func dropAttrs(
+	ctx context.Context,
+	cardinalityDetector cardinalitydetector.Detector,
+	attrs []attribute.KeyValue,
+) []attribute.KeyValue {
+	kept := make([]attribute.KeyValue, 0, len(attrs))
+
+	for _, attr := range attrs {
+		limited, ok := cardinalityDetector.LimitAttrs(ctx, []attribute.KeyValue{attr})
+		switch {
+		case !ok:
+		case limited == nil:
+			kept = append(kept, attr)
+		default:
+			// denied key is stripped
+			kept = append(kept, limited...)
+		}
+	}
+
+	return kept
+}
+
 // Shutdown implements trace.Tracer.
 func (t *tracer) Shutdown() {
 	t.cardinalityDetectorPool.Shutdown()
@@ -3,10 +3,14 @@ package trace
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+	"go.opentelemetry.io/otel/attribute"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestTracerSampleWithContextSpanSamplingAndNeverSampleDefault(t *testing.T) {
@@ -36,3 +40,115 @@ func TestTracerSampleWithContextSpanSamplingAndNeverAlwaysDefault(t *testing.T)
 	_, s := tracer.Start(ctx, "test")
 	must.False(s.SpanContext().IsSampled())
 }
+
+func TestTracerOverflowSpanNames(t *testing.T) {
+	must := require.New(t)
+	recorder := tracetest.NewSpanRecorder()
+	cardDectOpts := cardinalitydetector.NewOptions(
+		cardinalitydetector.WithMaxInstruments(1),
+		cardinalitydetector.WithMode(cardinalitydetector.ModeOverflow),
+	)
+	tp := NewTracerProvider(context.Background(), cardDectOpts, sdktrace.WithSpanProcessor(recorder))
+	tracer := tp.Tracer("test")
+
+	_, s := tracer.Start(context.Background(), "GET /orders")
+	s.End()
+	_, s = tracer.Start(context.Background(), "GET /orders/42")
+	s.End()
+
+	spans := recorder.Ended()
+	must.Len(spans, 2)
+	must.Equal("GET /orders", spans[0].Name())
+	must.Equal(cardinalitydetector.OverflowValue, spans[1].Name())
+}
+
+func TestTracerRulesLimitStartAttributes(t *testing.T) {
+	must := require.New(t)
+	recorder := tracetest.NewSpanRecorder()
+
+	rules, err := cardinalitydetector.ParseRules("GET /orders=>limit:1,deny:user.email")
+	must.NoError(err)
+
+	// attributes of spans aren't limited without rule, as TRACES_CARDINALITY_DETECTOR_MAX_CARDINALITY is 0
+	cardDectOpts := cardinalitydetector.NewOptions(
+		cardinalitydetector.WithMaxCardinality(0),
+		cardinalitydetector.WithMode(cardinalitydetector.ModeOverflow),
+		cardinalitydetector.WithRules(rules...),
+	)
+	tp := NewTracerProvider(context.Background(), cardDectOpts, sdktrace.WithSpanProcessor(recorder))
+	tracer := tp.Tracer("test")
+
+	for _, id := range []string{"1", "2"} {
+		_, s := tracer.Start(context.Background(), "GET /orders", trace.WithAttributes(
+			attribute.String("order.id", id),
+			attribute.String("user.email", "a@b.c"),
+		))
+		s.End()
+	}
+
+	// spans without rule are kept as is
+	_, s := tracer.Start(context.Background(), "GET /users", trace.WithAttributes(attribute.String("user.email", "a@b.c")))
+	s.End()
+
+	spans := recorder.Ended()
+	must.Len(spans, 3)
+	must.Equal([]attribute.KeyValue{attribute.String("order.id", "1")}, spans[0].Attributes())
+	must.Equal([]attribute.KeyValue{attribute.String("order.id", cardinalitydetector.OverflowValue)}, spans[1].Attributes())
+	must.Equal([]attribute.KeyValue{attribute.String("user.email", "a@b.c")}, spans[2].Attributes())
+
+	report := tp.CardinalityReport()
+	must.Len(report, 1)
+	must.Len(report[0].Instruments, 2)
+	must.Equal("GET /orders", report[0].Instruments[0].Name)
+	must.Equal("order.id", report[0].Instruments[0].Attributes[0].Key)
+}
+
+func TestTracerRulesDropStartAttributes(t *testing.T) {
+	must := require.New(t)
+	recorder := tracetest.NewSpanRecorder()
+
+	rules, err := cardinalitydetector.ParseRules("GET /orders=>limit:1")
+	must.NoError(err)
+
+	cardDectOpts := cardinalitydetector.NewOptions(
+		cardinalitydetector.WithMaxCardinality(0),
+		cardinalitydetector.WithRules(rules...),
+	)
+	tp := NewTracerProvider(context.Background(), cardDectOpts, sdktrace.WithSpanProcessor(recorder))
+	tracer := tp.Tracer("test")
+
+	link := trace.Link{SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})}
+	start := time.Unix(100, 0)
+
+	for _, id := range []string{"1", "2"} {
+		_, s := tracer.Start(context.Background(), "GET /orders",
+			trace.WithAttributes(attribute.String("order.id", id)),
+			trace.WithAttributes(attribute.String("http.method", "GET")),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithLinks(link),
+			trace.WithTimestamp(start),
+			trace.WithNewRoot(),
+		)
+		s.End()
+	}
+
+	// span with value over limit is kept without it
+	spans := recorder.Ended()
+	must.Len(spans, 2)
+	must.Equal([]attribute.KeyValue{
+		attribute.String("order.id", "1"),
+		attribute.String("http.method", "GET"),
+	}, spans[0].Attributes())
+	must.Equal([]attribute.KeyValue{attribute.String("http.method", "GET")}, spans[1].Attributes())
+
+	for _, s := range spans {
+		must.Equal(trace.SpanKindServer, s.SpanKind())
+		must.Len(s.Links(), 1)
+		must.Equal(link.SpanContext.SpanID(), s.Links()[0].SpanContext.SpanID())
+		must.Equal(start, s.StartTime())
+		must.False(s.Parent().IsValid())
+	}
+}
@@ -4,15 +4,21 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"time"
 
+	"github.com/pkg/errors"
+	"github.com/tel-io/tel/v2/pkg/loglevel"
+	"github.com/tel-io/tel/v2/pkg/zcore"
 	"github.com/tel-io/tel/v2/pkg/ztrace"
+	sdktrace "github.com/tel-io/tel/v2/sdk/trace"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	metricnoop "go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/trace"
 	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -34,6 +40,12 @@ type Telemetry struct {
 
 	traceProvider  trace.TracerProvider
 	metricProvider metric.MeterProvider
+
+	// metricsHandler serves prometheus pull endpoint, nil if disabled
+	metricsHandler http.Handler
+
+	// levels of logger outputs changeable at runtime
+	levels *loglevel.Levels
 }
 
 func NewNull() Telemetry {
@@ -48,18 +60,40 @@ func NewNull() Telemetry {
 	}
 }
 
+// NewWithProviders create telemetry with given logger, trace and metric providers.
+// No OTEL exporters are started and globals are not changed, useful for tests and custom pipelines
+func NewWithProviders(cfg Config, logger *zap.Logger, tp trace.TracerProvider, mp metric.MeterProvider) Telemetry {
+	return Telemetry{
+		cfg:            &cfg,
+		Logger:         logger,
+		trace:          tp.Tracer(GenServiceName(cfg.Namespace, cfg.Service) + "_tracer"),
+		traceProvider:  tp,
+		metricProvider: mp,
+	}
+}
+
 // NewSimple create simple logger without OTEL propagation
 func NewSimple(cfg Config) Telemetry {
+	levels := loglevel.New()
+
+	logger, err := newLogger(cfg, levels.Add(loglevel.OutputStdout, cfg.Level()))
+	handleErr(err, "zap build")
+
+	return newSimple(cfg, logger, levels)
+}
+
+func newSimple(cfg Config, logger *zap.Logger, levels *loglevel.Levels) Telemetry {
 	// required as it use for generate uid
 	rand.Seed(time.Now().Unix())
 
 	traceProvider := tracenoop.NewTracerProvider()
 	out := Telemetry{
 		cfg:            &cfg,
-		Logger:         newLogger(cfg),
+		Logger:         logger,
 		trace:          traceProvider.Tracer(instrumentationName),
 		traceProvider:  traceProvider,
 		metricProvider: metricnoop.NewMeterProvider(),
+		levels:         levels,
 	}
 
 	zap.ReplaceGlobals(out.Logger)
@@ -72,20 +106,67 @@ func NewSimple(cfg Config) Telemetry {
 }
 
 // New create telemetry instance
+// any initialization error is fatal, use NewE to handle errors
 func New(ctx context.Context, cfg Config, options ...Option) (Telemetry, func()) {
+	out, shutdown, err := NewE(ctx, cfg, options...)
+	handleErr(err, "telemetry initialization")
+
+	return out, func() {
+		ccx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := shutdown(ccx); err != nil {
+			out.Error("telemetry shutdown", Error(err))
+		}
+	}
+}
+
+// NewE create telemetry instance, errors of all parts are combined.
+// Returned telemetry is usable even with error: failed parts stay noop,
+// so caller decides whether to go on with degraded telemetry or to fail.
+// Shutdown returns combined errors of all started parts
+func NewE(ctx context.Context, cfg Config, options ...Option) (Telemetry, func(context.Context) error, error) {
 	for _, option := range options {
 		option.apply(&cfg)
 	}
 
-	out := NewSimple(cfg)
+	var errs error
+
+	if err := cfg.validateLevel(); err != nil {
+		errs = multierr.Append(errs, err)
+		cfg.LogLevel = zapcore.InfoLevel.String()
+	}
+
+	errs = multierr.Append(errs, cfg.validateDetectors())
+
+	levels := loglevel.New()
+
+	logger, err := newLogger(cfg, levels.Add(loglevel.OutputStdout, cfg.Level()))
+	if err != nil {
+		errs = multierr.Append(errs, errors.WithMessage(err, "zap build"))
+		logger = zap.NewNop()
+	}
+
+	out := newSimple(cfg, logger, levels)
 
 	var controls []controllers
 
 	if cfg.OtelConfig.Enable {
 		res := CreateRes(ctx, cfg)
 
-		// we're afraid that someone double this or miss something - that's why none exported options
-		controls = append(controls, withOtelLog(res), withOtelTrace(res), withOtelMetric(res))
+		// signals are enabled by config or injected components,
+		// metrics go first, self-metrics of logs and traces pipelines are created with its provider
+		if cfg.Metrics.Enable || cfg.Metrics.Prometheus || len(cfg.metricReaders) > 0 {
+			controls = append(controls, withOtelMetric(res))
+		}
+
+		if cfg.Logs.Enable || len(cfg.logExporters)+len(cfg.logProcessors) > 0 {
+			controls = append(controls, withOtelLog(res))
+		}
+
+		if cfg.Traces.Enable || len(cfg.spanExporters)+len(cfg.spanProcessors) > 0 {
+			controls = append(controls, withOtelTrace(res))
+		}
 
 		if cfg.Logs.OtelClient {
 			controls = append(controls, withOtelClientLog())
@@ -100,21 +181,28 @@ func New(ctx context.Context, cfg Config, options ...Option) (Telemetry, func())
 		controls = append(controls, withMonitor())
 	}
 
-	var closers []func(context.Context)
+	var closers []func(context.Context) error
+
 	for _, fn := range controls {
-		closers = append(closers, fn.apply(ctx, &out))
+		closer, err := fn.apply(ctx, &out)
+		errs = multierr.Append(errs, err)
+
+		if closer != nil {
+			closers = append(closers, closer)
+		}
 	}
 
 	SetGlobal(out)
 
-	return out, func() {
-		ccx, cancel := context.WithTimeout(context.Background(), time.Minute)
-		defer cancel()
+	return out, func(ccx context.Context) error {
+		var errs error
 
 		for _, cb := range closers {
-			cb(ccx)
+			errs = multierr.Append(errs, cb(ccx))
 		}
-	}
+
+		return errs
+	}, errs
 }
 
 // IsDebug if ENV DEBUG was true
@@ -122,8 +210,13 @@ func (t Telemetry) IsDebug() bool {
 	return t.cfg.Debug
 }
 
-// LogLevel safe pars log level, in case of error return InfoLevel
+// LogLevel safe pars log level, in case of error return InfoLevel.
+// Levels changed at runtime are respected: the most verbose level of outputs is returned
 func (t Telemetry) LogLevel() zapcore.Level {
+	if t.levels != nil {
+		return t.levels.Min()
+	}
+
 	if t.cfg == nil {
 		return zapcore.InfoLevel
 	}
@@ -136,6 +229,11 @@ func (t Telemetry) LogLevel() zapcore.Level {
 	return lvl
 }
 
+// LogLevels returns runtime control of log levels per output, nil for telemetry not created by New
+func (t Telemetry) LogLevels() *loglevel.Levels {
+	return t.levels
+}
+
 // WithContext put new copy of telemetry into context
 func (t Telemetry) WithContext(ctx context.Context) context.Context {
 	return WithContext(ctx, t)
@@ -239,6 +337,10 @@ func (t *Telemetry) StartSpan(ctx context.Context, name string, opts ...trace.Sp
 	tele := t.WithSpan(span)
 	tele.PutSpan(span)
 
+	if forced, ok := sdktrace.SpanSamplingFromContext(ctx); ok && forced {
+		tele.forceLevel()
+	}
+
 	ctx = WrapContext(ctx, tele)
 
 	UpdateTraceFields(ctx)
@@ -252,7 +354,7 @@ func (t Telemetry) WithSpan(s trace.Span) *Telemetry {
 	t.Logger = t.Logger.WithOptions(
 		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
 			traceCore := ztrace.New(
-				t.LogLevel(),
+				t.spanLevel(),
 				s,
 				ztrace.WithTrackLogFields(t.cfg.Traces.EnableSpanTrackLogFields),
 				ztrace.WithTrackLogMessage(t.cfg.Traces.EnableSpanTrackLogMessage),
@@ -265,6 +367,28 @@ func (t Telemetry) WithSpan(s trace.Span) *Telemetry {
 	return &t
 }
 
+// forceLevel writes logs of forced sampled request from TRACES_FORCE_SAMPLE_LOG_LEVEL regardless of outputs level
+func (t *Telemetry) forceLevel() {
+	lvl := t.cfg.Traces.ForceSample.LogLevel
+
+	t.Logger = t.Logger.WithOptions(
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zcore.NewLevel(core, lvl)
+		}),
+	)
+}
+
+// spanLevel is level of log duplication into span, spans are exported via OTLP as logs do
+func (t Telemetry) spanLevel() zapcore.LevelEnabler {
+	if t.levels != nil {
+		if lvl, ok := t.levels.Level(loglevel.OutputOTLP); ok {
+			return lvl
+		}
+	}
+
+	return t.LogLevel()
+}
+
 // Printf expose fx.Printer interface as debug output
 func (t *Telemetry) Printf(msg string, items ...interface{}) {
 	t.Debug(fmt.Sprintf(msg, items...))
@@ -2,9 +2,37 @@ package tel
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2/otlplog/logskd"
+	"github.com/tel-io/tel/v2/pkg/cardinalitydetector"
+	"github.com/tel-io/tel/v2/pkg/loglevel"
+	"github.com/tel-io/tel/v2/pkg/selfmetric"
+	"github.com/tel-io/tel/v2/pkg/wal"
+	telsdkmetric "github.com/tel-io/tel/v2/sdk/metric"
+	telsdktrace "github.com/tel-io/tel/v2/sdk/trace"
+	"go.opentelemetry.io/otel"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // check whole context stack: WithContext, updateContext, FromCtx
@@ -67,3 +95,604 @@ func Test_telemetry_With(t *testing.T) {
 		buf.Reset() // clean
 	})
 }
+
+func TestNewE(t *testing.T) {
+	prev := Global()
+	defer SetGlobal(prev)
+
+	cfg := DefaultConfig()
+	cfg.LogLevel = "loud"
+	cfg.MonitorConfig.Enable = false
+	cfg.OtelConfig.Enable = true
+	cfg.OtelConfig.Raw.CA = []byte("not a certificate")
+
+	tele, shutdown, err := NewE(context.Background(), cfg)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrCaAppend)
+	assert.Len(t, multierr.Errors(err), 4, "level, logs, traces and metrics")
+
+	// failed parts stay noop, telemetry is usable
+	assert.Equal(t, zapcore.InfoLevel, tele.LogLevel())
+	span, ctx := tele.StartSpan(tele.Ctx(), "span")
+	FromCtx(ctx).Info("degraded")
+	span.End()
+	assert.False(t, span.IsRecording())
+
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestNewE_HTTPJSON(t *testing.T) {
+	prev, prevMeter, prevTracer := Global(), otel.GetMeterProvider(), otel.GetTracerProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTracerProvider(prevTracer)
+	}()
+
+	cfg := DefaultConfig()
+	cfg.MonitorConfig.Enable = false
+	cfg.Protocol = ProtocolHTTPJSON
+
+	_, shutdown, err := NewE(context.Background(), cfg)
+	assert.ErrorIs(t, err, ErrUnsupportedProtocol)
+	assert.Len(t, multierr.Errors(err), 2, "traces and metrics")
+	assert.NoError(t, shutdown(context.Background()))
+
+	// logs only
+	cfg.Traces.Enable = false
+	cfg.Metrics.Enable = false
+
+	_, shutdown, err = NewE(context.Background(), cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestTelemetry_LogLevels(t *testing.T) {
+	prev := Global()
+	defer SetGlobal(prev)
+
+	cfg := DefaultConfig()
+	cfg.LogLevel = "info"
+	cfg.MonitorConfig.Enable = false
+	cfg.OtelConfig.Enable = false
+
+	tele, _, err := NewE(context.Background(), cfg)
+	assert.NoError(t, err)
+	assert.False(t, tele.Core().Enabled(zapcore.DebugLevel))
+
+	assert.NoError(t, tele.LogLevels().Set(loglevel.OutputStdout, zapcore.DebugLevel, time.Hour))
+	assert.True(t, tele.Core().Enabled(zapcore.DebugLevel))
+	assert.Equal(t, zapcore.DebugLevel, tele.LogLevel())
+}
+
+func TestTelemetry_Prometheus(t *testing.T) {
+	prev, prevProvider := Global(), otel.GetMeterProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevProvider)
+	}()
+
+	cfg := DefaultConfig()
+	cfg.MonitorConfig.Enable = false
+	cfg.Logs.Enable = false
+	cfg.Traces.Enable = false
+	cfg.Metrics.Enable = false
+	cfg.Metrics.Prometheus = true
+
+	tele, shutdown, err := NewE(context.Background(), cfg)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+	require.NotNil(t, tele.metricsHandler)
+
+	counter, err := tele.Meter("test").Int64Counter("test.requests")
+	require.NoError(t, err)
+	counter.Add(tele.Ctx(), 3)
+
+	rec := httptest.NewRecorder()
+	tele.metricsHandler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "test_requests_total")
+}
+
+func TestCreateRes(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Service = "api"
+	cfg.Environment = "prod"
+
+	t.Run("pod", func(t *testing.T) {
+		t.Setenv("K8S_POD_NAME", "api-7d9f-x2x")
+		t.Setenv("K8S_NAMESPACE_NAME", "payments")
+
+		set := CreateRes(context.Background(), cfg).Set()
+
+		v, ok := set.Value(ServiceInstanceIDKey)
+		assert.True(t, ok)
+		assert.Equal(t, "api-7d9f-x2x", v.AsString())
+
+		v, _ = set.Value(semconv.K8SNamespaceNameKey)
+		assert.Equal(t, "payments", v.AsString())
+
+		v, _ = set.Value(semconv.DeploymentEnvironmentKey)
+		assert.Equal(t, "prod", v.AsString())
+
+		_, ok = set.Value(semconv.ProcessPIDKey)
+		assert.True(t, ok)
+	})
+
+	t.Run("no detectors", func(t *testing.T) {
+		cfg := cfg
+		cfg.ResourceDetectors = []string{DetectorNone}
+		assert.NoError(t, cfg.validateDetectors())
+
+		set := CreateRes(context.Background(), cfg).Set()
+
+		v, ok := set.Value(ServiceInstanceIDKey)
+		assert.True(t, ok)
+		assert.Contains(t, v.AsString(), "api-")
+
+		_, ok = set.Value(semconv.DeploymentEnvironmentKey)
+		assert.False(t, ok)
+	})
+
+	cfg.ResourceDetectors = []string{"cloud"}
+	assert.ErrorIs(t, cfg.validateDetectors(), ErrUnknownDetector)
+}
+
+type testLogExporter struct {
+	mu   sync.Mutex
+	logs []logskd.Log
+}
+
+func (e *testLogExporter) ExportLogs(_ context.Context, logs []logskd.Log) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.logs = append(e.logs, logs...)
+
+	return nil
+}
+
+func (e *testLogExporter) Shutdown(context.Context) error { return nil }
+
+func TestNewE_InjectedComponents(t *testing.T) {
+	prev, prevMeter, prevTracer := Global(), otel.GetMeterProvider(), otel.GetTracerProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTracerProvider(prevTracer)
+	}()
+
+	cfg := DefaultConfig()
+	cfg.MonitorConfig.Enable = false
+	// OTLP exporters are replaced
+	cfg.Logs.Enable = false
+	cfg.Traces.Enable = false
+	cfg.Metrics.Enable = false
+
+	spans := tracetest.NewSpanRecorder()
+	spanExp := tracetest.NewInMemoryExporter()
+	logExp := &testLogExporter{}
+	reader := sdkmetric.NewManualReader()
+
+	tele, shutdown, err := NewE(context.Background(), cfg,
+		WithTraceSampler(sdktrace.AlwaysSample()),
+		WithSpanProcessor(spans),
+		WithSpanExporter(spanExp),
+		WithLogExporter(logExp),
+		WithMetricReader(reader),
+	)
+	require.NoError(t, err)
+
+	span, ctx := tele.StartSpan(tele.Ctx(), "injected")
+	FromCtx(ctx).Info("injected log")
+	span.End()
+
+	counter, err := tele.Meter("test").Int64Counter("injected.requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+
+	var names []string
+	for _, sm := range data.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names = append(names, m.Name)
+		}
+	}
+
+	assert.Contains(t, names, "injected.requests")
+
+	// in-memory exporter forgets spans on shutdown
+	flusher, ok := tele.traceProvider.(interface{ ForceFlush(context.Context) error })
+	require.True(t, ok)
+	require.NoError(t, flusher.ForceFlush(context.Background()))
+
+	assert.Len(t, spans.Ended(), 1)
+	require.Len(t, spanExp.GetSpans(), 1)
+	assert.Equal(t, "injected", spanExp.GetSpans()[0].Name)
+
+	require.NoError(t, shutdown(context.Background()))
+
+	logExp.mu.Lock()
+	defer logExp.mu.Unlock()
+	assert.NotEmpty(t, logExp.logs)
+}
+
+func TestTelemetry_ForceSample(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Traces.ForceSample.Enable = true
+
+	propagator, err := cfg.OtelConfig.propagator()
+	require.NoError(t, err)
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(telsdktrace.NewSampler(sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(spans),
+	)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	tele := NewWithProviders(cfg, zap.New(core), tp, metricnoop.NewMeterProvider())
+
+	for name, header := range map[string]http.Header{
+		"header":  {"X-Tel-Force-Sample": []string{"1"}},
+		"baggage": {"Baggage": []string{"tel.force_sample=1"}},
+		"none":    {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			forced := name != "none"
+
+			ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(header))
+
+			span, ctx := tele.StartSpan(ctx, "GET /orders")
+			FromCtx(ctx).Debug("request details")
+			span.End()
+
+			assert.Equal(t, forced, span.SpanContext().IsSampled())
+			assert.Equal(t, forced, logs.FilterMessage("request details").Len() == 1)
+
+			// downstream services get forced decision
+			out := propagation.HeaderCarrier(http.Header{})
+			propagator.Inject(ctx, out)
+			assert.Equal(t, forced, out.Get("X-Tel-Force-Sample") == "1")
+
+			_ = logs.TakeAll()
+		})
+	}
+}
+
+func TestNewE_SelfMetrics(t *testing.T) {
+	prev, prevMeter, prevTracer := Global(), otel.GetMeterProvider(), otel.GetTracerProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTracerProvider(prevTracer)
+	}()
+
+	cfg := DefaultConfig()
+	cfg.MonitorConfig.Enable = false
+	cfg.Logs.Enable = false
+	cfg.Traces.Enable = false
+	cfg.Metrics.Enable = false
+
+	reader := sdkmetric.NewManualReader()
+
+	_, shutdown, err := NewE(context.Background(), cfg,
+		WithLogExporter(&testLogExporter{}),
+		WithMetricReader(reader),
+	)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+
+	var names []string
+	for _, sm := range data.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names = append(names, m.Name)
+		}
+	}
+
+	// logs pipeline is created after metrics one, its self-metrics aren't bound to noop provider
+	assert.Contains(t, names, selfmetric.MetricQueueSize)
+}
+
+func TestNewE_DelayedProcessorMetrics(t *testing.T) {
+	prev, prevMeter, prevTracer := Global(), otel.GetMeterProvider(), otel.GetTracerProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTracerProvider(prevTracer)
+	}()
+
+	cfg := DefaultConfig()
+	cfg.MonitorConfig.Enable = false
+	cfg.Logs.Enable = false
+	cfg.Metrics.Enable = false
+	cfg.Traces.Processor = TracesProcessorDelayed
+	// nothing is exported, trace is dropped by processor
+	cfg.Traces.Delayed.Fraction = 0
+	cfg.Addr = "127.0.0.1:1"
+
+	reader := sdkmetric.NewManualReader()
+
+	tele, shutdown, err := NewE(context.Background(), cfg,
+		WithTraceSampler(sdktrace.AlwaysSample()),
+		WithMetricReader(reader),
+	)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+
+	span, _ := tele.StartSpan(tele.Ctx(), "delayed")
+	span.End()
+
+	flusher, ok := tele.traceProvider.(interface{ ForceFlush(context.Context) error })
+	require.True(t, ok)
+	require.NoError(t, flusher.ForceFlush(context.Background()))
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+
+	var spans metricdata.Sum[int64]
+	for _, sm := range data.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == telsdktrace.MetricDelayedSpans {
+				spans, _ = m.Data.(metricdata.Sum[int64])
+			}
+		}
+	}
+
+	require.Len(t, spans.DataPoints, 1)
+	assert.Equal(t, int64(1), spans.DataPoints[0].Value)
+
+	decision, _ := spans.DataPoints[0].Attributes.Value(telsdktrace.DecisionKey)
+	assert.Equal(t, "dropped", decision.AsString())
+}
+
+func TestNewE_BatchProcessorMetrics(t *testing.T) {
+	prev, prevMeter, prevTracer := Global(), otel.GetMeterProvider(), otel.GetTracerProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTracerProvider(prevTracer)
+	}()
+
+	cfg := DefaultConfig()
+	cfg.MonitorConfig.Enable = false
+	cfg.Logs.Enable = false
+	cfg.Metrics.Enable = false
+	cfg.Addr = "127.0.0.1:1"
+
+	reader := sdkmetric.NewManualReader()
+
+	_, shutdown, err := NewE(context.Background(), cfg, WithMetricReader(reader))
+	require.NoError(t, err)
+	defer func() { _ = shutdown(context.Background()) }()
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+
+	var queue metricdata.Gauge[int64]
+	for _, sm := range data.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == selfmetric.MetricQueueSize {
+				queue, _ = m.Data.(metricdata.Gauge[int64])
+			}
+		}
+	}
+
+	require.Len(t, queue.DataPoints, 1)
+
+	signal, _ := queue.DataPoints[0].Attributes.Value(selfmetric.SignalKey)
+	assert.Equal(t, selfmetric.SignalTraces, signal.AsString())
+}
+
+func TestTelemetry_ForceSampleDisabled(t *testing.T) {
+	cfg := DefaultConfig()
+	require.False(t, cfg.Traces.ForceSample.Enable)
+
+	propagator, err := cfg.OtelConfig.propagator()
+	require.NoError(t, err)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(telsdktrace.NewSampler(sdktrace.NeverSample())))
+	tele := NewWithProviders(cfg, zap.NewNop(), tp, metricnoop.NewMeterProvider())
+
+	// header of untrusted client is ignored by default
+	header := http.Header{"X-Tel-Force-Sample": []string{"1"}}
+	ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	span, _ := tele.StartSpan(ctx, "GET /orders")
+	span.End()
+
+	assert.False(t, span.SpanContext().IsSampled())
+}
+
+func TestNewE_CardinalityMetrics(t *testing.T) {
+	prev, prevMeter, prevTracer := Global(), otel.GetMeterProvider(), otel.GetTracerProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTracerProvider(prevTracer)
+	}()
+
+	for _, enable := range []bool{true, false} {
+		cfg := DefaultConfig()
+		cfg.MonitorConfig.Enable = false
+		cfg.Logs.Enable = false
+		cfg.Traces.Enable = false
+		cfg.Metrics.Enable = false
+		cfg.Metrics.CardinalityDetector.Enable = true
+		cfg.Metrics.CardinalityDetector.MaxCardinality = 10
+		cfg.Metrics.CardinalityDetector.MaxInstruments = 500
+		cfg.Metrics.CardinalityDetector.Gauges = enable
+
+		reader := sdkmetric.NewManualReader()
+
+		tele, shutdown, err := NewE(context.Background(), cfg, WithMetricReader(reader))
+		require.NoError(t, err)
+
+		counter, err := tele.Meter("test").Int64Counter("test.requests")
+		require.NoError(t, err)
+		counter.Add(tele.Ctx(), 1)
+
+		var data metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &data))
+
+		var names []string
+		for _, sm := range data.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				names = append(names, m.Name)
+			}
+		}
+
+		assert.Equal(t, enable, slices.Contains(names, cardinalitydetector.MetricInstruments))
+		require.NoError(t, shutdown(context.Background()))
+	}
+}
+
+func TestOpenBuffer(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	cfg := DefaultConfig()
+	cfg.Buffer.Enable = true
+
+	tele := &Telemetry{Logger: zap.NewNop(), cfg: &cfg}
+
+	dir := filepath.Join(os.TempDir(), "tel", GenServiceName(cfg.Namespace, cfg.Service))
+
+	buf := openBuffer(tele, selfmetric.SignalTraces)
+	require.NotNil(t, buf)
+	assert.DirExists(t, filepath.Join(dir, selfmetric.SignalTraces))
+	require.NoError(t, buf.Write([]byte("request")))
+
+	// running instance of service on the same host takes the next slot
+	other := openBuffer(tele, selfmetric.SignalTraces)
+	require.NotNil(t, other)
+	assert.DirExists(t, filepath.Join(dir, "1", selfmetric.SignalTraces))
+	assert.Zero(t, other.Len())
+
+	assert.NoError(t, closeBuffer(buf))
+	assert.ErrorIs(t, buf.Write([]byte("request")), wal.ErrClosed)
+	assert.NoError(t, closeBuffer(nil))
+
+	// restarted instance picks up entries of its predecessor
+	buf = openBuffer(tele, selfmetric.SignalTraces)
+	require.NotNil(t, buf)
+	assert.Equal(t, 1, buf.Len())
+
+	assert.NoError(t, closeBuffer(buf))
+	assert.NoError(t, closeBuffer(other))
+
+	// explicit dir is used by single instance
+	cfg.Buffer.Dir = t.TempDir()
+
+	buf = openBuffer(tele, selfmetric.SignalTraces)
+	require.NotNil(t, buf)
+	assert.Nil(t, openBuffer(tele, selfmetric.SignalTraces))
+	assert.NoError(t, closeBuffer(buf))
+}
+
+func TestNewE_ExemplarFilter(t *testing.T) {
+	prev, prevMeter, prevTracer := Global(), otel.GetMeterProvider(), otel.GetTracerProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTracerProvider(prevTracer)
+	}()
+
+	for filter, exemplars := range map[string]int{
+		telsdkmetric.ExemplarFilterTraceBased: 1,
+		telsdkmetric.ExemplarFilterAlwaysOff:  0,
+	} {
+		t.Run(filter, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MonitorConfig.Enable = false
+			cfg.Logs.Enable = false
+			cfg.Traces.Enable = false
+			cfg.Metrics.Enable = false
+			cfg.Metrics.ExemplarFilter = filter
+
+			reader := sdkmetric.NewManualReader()
+
+			tele, shutdown, err := NewE(context.Background(), cfg,
+				WithSpanExporter(tracetest.NewInMemoryExporter()),
+				WithTraceSampler(sdktrace.AlwaysSample()),
+				WithMetricReader(reader),
+			)
+			require.NoError(t, err)
+			defer func() { assert.NoError(t, shutdown(context.Background())) }()
+
+			histogram, err := tele.Meter("test").Float64Histogram("latency")
+			require.NoError(t, err)
+
+			span, ctx := tele.StartSpan(tele.Ctx(), "request")
+			histogram.Record(ctx, 1.5)
+			span.End()
+
+			var data metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &data))
+
+			var found bool
+			for _, sm := range data.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					if hist, ok := m.Data.(metricdata.Histogram[float64]); ok && m.Name == "latency" {
+						require.Len(t, hist.DataPoints, 1)
+						assert.Len(t, hist.DataPoints[0].Exemplars, exemplars)
+						found = true
+					}
+				}
+			}
+			assert.True(t, found)
+		})
+	}
+
+	cfg := DefaultConfig()
+	cfg.MonitorConfig.Enable = false
+	cfg.Logs.Enable = false
+	cfg.Traces.Enable = false
+	cfg.Metrics.Enable = false
+	cfg.Metrics.ExemplarFilter = "sometimes"
+
+	_, shutdown, err := NewE(context.Background(), cfg, WithMetricReader(sdkmetric.NewManualReader()))
+	assert.ErrorIs(t, err, telsdkmetric.ErrUnknownExemplarFilter)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestNewE_TracesCardinalityEstimator(t *testing.T) {
+	prev, prevMeter, prevTracer := Global(), otel.GetMeterProvider(), otel.GetTracerProvider()
+	defer func() {
+		SetGlobal(prev)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTracerProvider(prevTracer)
+	}()
+
+	for mode, expected := range map[string]error{
+		cardinalitydetector.ModeOverflow: nil,
+		cardinalitydetector.ModeDrop:     cardinalitydetector.ErrEstimatorMode,
+	} {
+		t.Run(mode, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MonitorConfig.Enable = false
+			cfg.Logs.Enable = false
+			cfg.Metrics.Enable = false
+			cfg.Traces.Enable = false
+			cfg.Traces.CardinalityDetector.MaxCardinality = 10
+			cfg.Traces.CardinalityDetector.Mode = mode
+			cfg.Traces.CardinalityDetector.Estimator = cardinalitydetector.EstimatorHyperLogLog
+			cfg.Traces.CardinalityDetector.Window = time.Hour
+
+			_, shutdown, err := NewE(context.Background(), cfg, WithSpanExporter(tracetest.NewInMemoryExporter()))
+			if expected == nil {
+				require.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, expected)
+			}
+
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
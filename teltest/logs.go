@@ -0,0 +1,173 @@
+package teltest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tel-io/tel/v2/otlplog/logskd"
+	"github.com/tel-io/tel/v2/pkg/attrencoder"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ logskd.Exporter = (*LogExporter)(nil)
+
+// LogExporter is in-memory logskd.Exporter
+type LogExporter struct {
+	mu   sync.Mutex
+	logs []Log
+}
+
+func NewLogExporter() *LogExporter {
+	return &LogExporter{}
+}
+
+// ExportLogs stores logs in memory
+func (e *LogExporter) ExportLogs(_ context.Context, logs []logskd.Log) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, l := range logs {
+		e.logs = append(e.logs, newLog(l))
+	}
+
+	return nil
+}
+
+// Shutdown does nothing, logs stay available
+func (e *LogExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// Logs returns copy of stored logs
+func (e *LogExporter) Logs() Logs {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	out := make(Logs, len(e.logs))
+	copy(out, e.logs)
+
+	return out
+}
+
+// Reset removes stored logs
+func (e *LogExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.logs = nil
+}
+
+// Log is recorded log message
+type Log struct {
+	Name    string
+	Time    time.Time
+	Level   zapcore.Level
+	Message string
+	Fields  map[attribute.Key]attribute.Value
+	TraceID trace.TraceID
+	SpanID  trace.SpanID
+}
+
+func newLog(l logskd.Log) Log {
+	out := Log{
+		Name:   l.Name(),
+		Time:   time.Unix(0, int64(l.Time())),
+		Fields: make(map[attribute.Key]attribute.Value, len(l.KV())),
+	}
+
+	for _, kv := range l.Attributes() {
+		if kv.Key == logskd.LevelKey {
+			_ = out.Level.UnmarshalText([]byte(kv.Value.AsString()))
+		}
+	}
+
+	for _, kv := range l.KV() {
+		if kv.Key == attrencoder.MsgKey {
+			out.Message = kv.Value.AsString()
+			continue
+		}
+
+		out.Fields[kv.Key] = kv.Value
+	}
+
+	copy(out.TraceID[:], l.TraceID())
+	copy(out.SpanID[:], l.SpanID())
+
+	return out
+}
+
+// Field returns value of field key
+func (l Log) Field(key string) (attribute.Value, bool) {
+	v, ok := l.Fields[attribute.Key(key)]
+	return v, ok
+}
+
+// Logs is list of recorded logs with query helpers
+type Logs []Log
+
+// Filter returns logs matching fn
+func (l Logs) Filter(fn func(Log) bool) Logs {
+	var out Logs
+
+	for _, v := range l {
+		if fn(v) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// Level returns logs of level lvl
+func (l Logs) Level(lvl zapcore.Level) Logs {
+	return l.Filter(func(v Log) bool { return v.Level == lvl })
+}
+
+// Message returns logs with message msg
+func (l Logs) Message(msg string) Logs {
+	return l.Filter(func(v Log) bool { return v.Message == msg })
+}
+
+// With returns logs having all provided fields with equal values
+func (l Logs) With(kv ...attribute.KeyValue) Logs {
+	return l.Filter(func(v Log) bool {
+		for _, f := range kv {
+			if val, ok := v.Fields[f.Key]; !ok || val != f.Value {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Has returns logs having all provided field keys
+func (l Logs) Has(keys ...string) Logs {
+	return l.Filter(func(v Log) bool {
+		for _, k := range keys {
+			if _, ok := v.Fields[attribute.Key(k)]; !ok {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Span returns logs written within span with id
+func (l Logs) Span(id trace.SpanID) Logs {
+	return l.Filter(func(v Log) bool { return v.SpanID == id })
+}
+
+// Messages returns messages of logs
+func (l Logs) Messages() []string {
+	out := make([]string, 0, len(l))
+	for _, v := range l {
+		out = append(out, v.Message)
+	}
+
+	return out
+}
@@ -0,0 +1,136 @@
+package teltest
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Metrics is collected state of metrics with query helpers
+type Metrics struct {
+	metricdata.ResourceMetrics
+}
+
+func collect(ctx context.Context, reader sdkmetric.Reader) (Metrics, error) {
+	var m Metrics
+	err := reader.Collect(ctx, &m.ResourceMetrics)
+
+	return m, err
+}
+
+// Metric returns metric by name
+func (m Metrics) Metric(name string) (metricdata.Metrics, bool) {
+	for _, sm := range m.ScopeMetrics {
+		for _, v := range sm.Metrics {
+			if v.Name == name {
+				return v, true
+			}
+		}
+	}
+
+	return metricdata.Metrics{}, false
+}
+
+// Names returns names of all collected metrics
+func (m Metrics) Names() []string {
+	var out []string
+
+	for _, sm := range m.ScopeMetrics {
+		for _, v := range sm.Metrics {
+			out = append(out, v.Name)
+		}
+	}
+
+	return out
+}
+
+// Int64 returns sum of int64 counter or gauge data points having all attrs
+func (m Metrics) Int64(name string, attrs ...attribute.KeyValue) (int64, bool) {
+	v, ok := m.Metric(name)
+	if !ok {
+		return 0, false
+	}
+
+	switch data := v.Data.(type) {
+	case metricdata.Sum[int64]:
+		return sumPoints(data.DataPoints, attrs)
+	case metricdata.Gauge[int64]:
+		return sumPoints(data.DataPoints, attrs)
+	default:
+		return 0, false
+	}
+}
+
+// Float64 returns sum of float64 counter or gauge data points having all attrs
+func (m Metrics) Float64(name string, attrs ...attribute.KeyValue) (float64, bool) {
+	v, ok := m.Metric(name)
+	if !ok {
+		return 0, false
+	}
+
+	switch data := v.Data.(type) {
+	case metricdata.Sum[float64]:
+		return sumPoints(data.DataPoints, attrs)
+	case metricdata.Gauge[float64]:
+		return sumPoints(data.DataPoints, attrs)
+	default:
+		return 0, false
+	}
+}
+
+// HistogramCount returns count of histogram records having all attrs
+func (m Metrics) HistogramCount(name string, attrs ...attribute.KeyValue) (uint64, bool) {
+	v, ok := m.Metric(name)
+	if !ok {
+		return 0, false
+	}
+
+	var (
+		count uint64
+		found bool
+	)
+
+	switch data := v.Data.(type) {
+	case metricdata.Histogram[int64]:
+		for _, dp := range data.DataPoints {
+			if hasAttributes(dp.Attributes, attrs) {
+				count, found = count+dp.Count, true
+			}
+		}
+	case metricdata.Histogram[float64]:
+		for _, dp := range data.DataPoints {
+			if hasAttributes(dp.Attributes, attrs) {
+				count, found = count+dp.Count, true
+			}
+		}
+	}
+
+	return count, found
+}
+
+func sumPoints[N int64 | float64](points []metricdata.DataPoint[N], attrs []attribute.KeyValue) (N, bool) {
+	var (
+		sum   N
+		found bool
+	)
+
+	for _, dp := range points {
+		if hasAttributes(dp.Attributes, attrs) {
+			sum, found = sum+dp.Value, true
+		}
+	}
+
+	return sum, found
+}
+
+func hasAttributes(set attribute.Set, attrs []attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if v, ok := set.Value(kv.Key); !ok || v != kv.Value {
+			return false
+		}
+	}
+
+	return true
+}
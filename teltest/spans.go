@@ -0,0 +1,124 @@
+package teltest
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans is list of ended spans with query helpers
+type Spans []sdktrace.ReadOnlySpan
+
+// Filter returns spans matching fn
+func (s Spans) Filter(fn func(sdktrace.ReadOnlySpan) bool) Spans {
+	var out Spans
+
+	for _, v := range s {
+		if fn(v) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// Name returns spans with name
+func (s Spans) Name(name string) Spans {
+	return s.Filter(func(v sdktrace.ReadOnlySpan) bool { return v.Name() == name })
+}
+
+// With returns spans having all provided attributes with equal values
+func (s Spans) With(kv ...attribute.KeyValue) Spans {
+	return s.Filter(func(v sdktrace.ReadOnlySpan) bool {
+		set := attribute.NewSet(v.Attributes()...)
+
+		for _, f := range kv {
+			if val, ok := set.Value(f.Key); !ok || val != f.Value {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// ChildrenOf returns direct children of parent
+func (s Spans) ChildrenOf(parent sdktrace.ReadOnlySpan) Spans {
+	id := parent.SpanContext().SpanID()
+
+	return s.Filter(func(v sdktrace.ReadOnlySpan) bool { return v.Parent().SpanID() == id })
+}
+
+// Roots returns spans which parent is not in the list
+func (s Spans) Roots() Spans {
+	ids := make(map[trace.SpanID]struct{}, len(s))
+	for _, v := range s {
+		ids[v.SpanContext().SpanID()] = struct{}{}
+	}
+
+	return s.Filter(func(v sdktrace.ReadOnlySpan) bool {
+		_, ok := ids[v.Parent().SpanID()]
+		return !ok
+	})
+}
+
+// First returns the first span, nil if list is empty
+func (s Spans) First() sdktrace.ReadOnlySpan {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return s[0]
+}
+
+// Names returns names of spans
+func (s Spans) Names() []string {
+	out := make([]string, 0, len(s))
+	for _, v := range s {
+		out = append(out, v.Name())
+	}
+
+	return out
+}
+
+// SpanTree is span with its children
+type SpanTree struct {
+	Span     sdktrace.ReadOnlySpan
+	Children []*SpanTree
+}
+
+// Trees builds span trees from roots
+func (s Spans) Trees() []*SpanTree {
+	roots := s.Roots()
+
+	out := make([]*SpanTree, 0, len(roots))
+	for _, root := range roots {
+		out = append(out, s.tree(root))
+	}
+
+	return out
+}
+
+func (s Spans) tree(root sdktrace.ReadOnlySpan) *SpanTree {
+	node := &SpanTree{Span: root}
+	for _, child := range s.ChildrenOf(root) {
+		node.Children = append(node.Children, s.tree(child))
+	}
+
+	return node
+}
+
+// Find returns the first node of tree with name in depth-first order, nil if not found
+func (t *SpanTree) Find(name string) *SpanTree {
+	if t.Span.Name() == name {
+		return t
+	}
+
+	for _, child := range t.Children {
+		if found := child.Find(name); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
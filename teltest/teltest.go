@@ -0,0 +1,113 @@
+// Package teltest provides tel.Telemetry backed by in-memory recorders of logs, spans and metrics
+// with helpers to query what code under test has produced.
+package teltest
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/tel-io/tel/v2"
+	"github.com/tel-io/tel/v2/otlplog/logskd"
+	"github.com/tel-io/tel/v2/pkg/zcore"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Harness holds telemetry and its in-memory recorders
+type Harness struct {
+	tel.Telemetry
+
+	LogExporter    *LogExporter
+	SpanRecorder   *tracetest.SpanRecorder
+	MetricReader   *sdkmetric.ManualReader
+	TracerProvider *sdktrace.TracerProvider
+	MeterProvider  *sdkmetric.MeterProvider
+
+	tb testing.TB
+}
+
+// New creates harness and installs it as tel and otel global for the test duration,
+// previous globals are restored on cleanup. All levels of logs and all spans are recorded.
+func New(tb testing.TB) *Harness {
+	tb.Helper()
+
+	h := &Harness{
+		LogExporter:  NewLogExporter(),
+		SpanRecorder: tracetest.NewSpanRecorder(),
+		MetricReader: sdkmetric.NewManualReader(),
+		tb:           tb,
+	}
+
+	h.TracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(h.SpanRecorder),
+	)
+	h.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(h.MetricReader))
+
+	core := zcore.NewBodyCore(
+		logskd.NewSimpleLogProcessor(h.LogExporter),
+		zapcore.DebugLevel,
+		zcore.WithMaxMessageSize(math.MaxInt),
+	)
+
+	cfg := tel.DefaultDebugConfig()
+	cfg.Service = "teltest"
+
+	h.Telemetry = tel.NewWithProviders(cfg,
+		zap.New(core, zap.WithCaller(true), zap.AddStacktrace(zapcore.ErrorLevel)),
+		h.TracerProvider,
+		h.MeterProvider,
+	)
+
+	prevTel := tel.Global()
+	prevTP := otel.GetTracerProvider()
+	prevMP := otel.GetMeterProvider()
+
+	tel.SetGlobal(h.Telemetry)
+	otel.SetTracerProvider(h.TracerProvider)
+	otel.SetMeterProvider(h.MeterProvider)
+
+	tb.Cleanup(func() {
+		tel.SetGlobal(prevTel)
+		otel.SetTracerProvider(prevTP)
+		otel.SetMeterProvider(prevMP)
+
+		ctx := context.Background()
+		_ = h.TracerProvider.Shutdown(ctx)
+		_ = h.MeterProvider.Shutdown(ctx)
+	})
+
+	return h
+}
+
+// Ctx returns context with harness telemetry
+func (h *Harness) Ctx() context.Context {
+	return h.Telemetry.Ctx()
+}
+
+// Logs returns recorded logs
+func (h *Harness) Logs() Logs {
+	return h.LogExporter.Logs()
+}
+
+// Spans returns ended spans
+func (h *Harness) Spans() Spans {
+	return h.SpanRecorder.Ended()
+}
+
+// Metrics collects current state of metrics, test fails on collection error
+func (h *Harness) Metrics() Metrics {
+	h.tb.Helper()
+
+	m, err := collect(context.Background(), h.MetricReader)
+	if err != nil {
+		h.tb.Fatalf("collect metrics: %v", err)
+	}
+
+	return m
+}
@@ -0,0 +1,69 @@
+package teltest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tel-io/tel/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHarness(t *testing.T) {
+	h := New(t)
+
+	span, ctx := tel.FromCtx(h.Ctx()).StartSpan(h.Ctx(), "parent")
+	tel.FromCtx(ctx).Info("hello", tel.String("user", "bob"))
+
+	child, cctx := tel.FromCtx(ctx).StartSpan(ctx, "child")
+	tel.FromCtx(cctx).Warn("careful", tel.Int("n", 3))
+	child.End()
+	span.End()
+
+	counter, err := tel.Global().Meter("teltest").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("route", "/a")))
+	counter.Add(ctx, 5, metric.WithAttributes(attribute.String("route", "/b")))
+
+	t.Run("logs", func(t *testing.T) {
+		logs := h.Logs()
+
+		info := logs.Level(zapcore.InfoLevel)
+		require.Len(t, info, 1)
+		assert.Equal(t, "hello", info[0].Message)
+		assert.Len(t, logs.With(attribute.String("user", "bob")), 1)
+		assert.Len(t, logs.Level(zapcore.WarnLevel).Has("n"), 1)
+		assert.Empty(t, logs.Message("nope"))
+	})
+
+	t.Run("spans", func(t *testing.T) {
+		spans := h.Spans()
+		require.Len(t, spans, 2)
+
+		parent := spans.Name("parent").First()
+		require.NotNil(t, parent)
+		assert.Equal(t, []string{"child"}, spans.ChildrenOf(parent).Names())
+
+		trees := spans.Trees()
+		require.Len(t, trees, 1)
+		assert.Equal(t, "parent", trees[0].Span.Name())
+		assert.NotNil(t, trees[0].Find("child"))
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		m := h.Metrics()
+
+		v, ok := m.Int64("requests", attribute.String("route", "/a"))
+		assert.True(t, ok)
+		assert.Equal(t, int64(2), v)
+
+		v, ok = m.Int64("requests")
+		assert.True(t, ok)
+		assert.Equal(t, int64(7), v)
+
+		_, ok = m.Int64("requests", attribute.String("route", "/c"))
+		assert.False(t, ok)
+	})
+}